@@ -1,17 +1,33 @@
 package accounts
 
 import (
+	"bytes"
+	"context"
 	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
 	"math/big"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	logging "github.com/ipfs/go-log/v2"
 
-	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum"
+	gethaccounts "github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/allisterb/strac/blockchain"
+	"github.com/allisterb/strac/blockchain/chaintime"
 	"github.com/allisterb/strac/util"
 )
 
@@ -21,11 +37,30 @@ func NewAccount(WalletDir *string) error {
 	if WalletDir != nil {
 		log.Infof("Creating keystore file at %s...", *WalletDir)
 		log.Info("Enter the passphrase for this keystore file")
-		_, err := util.GetPassPhrase(true)
+		passphrase, err := util.GetPassPhrase(true)
 		if err != nil {
 			return err
 		}
-		return fmt.Errorf("not implemented")
+		if err := os.MkdirAll(*WalletDir, 0700); err != nil {
+			return util.WrapError(err, "could not create directory %s", *WalletDir)
+		}
+		privateKey, err := crypto.GenerateKey()
+		if err != nil {
+			return err
+		}
+		ks := keystore.NewKeyStore(*WalletDir, keystore.StandardScryptN, keystore.StandardScryptP)
+		address := crypto.PubkeyToAddress(privateKey.PublicKey)
+		if ks.HasAddress(address) {
+			return fmt.Errorf("a keystore file for account %v already exists in %s", address.Hex(), *WalletDir)
+		}
+		account, err := ks.ImportECDSA(privateKey, *passphrase)
+		if err != nil {
+			return util.WrapError(err, "could not create keystore file")
+		}
+		log.Infof("New Stratis account address: %v", account.Address.Hex())
+		log.Infof("Keystore file: %v", account.URL.Path)
+		log.Warnf("Make sure you back up the keystore file and remember the passphrase...there is no way to recover the account if you lose either.")
+		return nil
 	}
 	privateKey, err := crypto.GenerateKey()
 	if err != nil {
@@ -46,23 +81,169 @@ func NewAccount(WalletDir *string) error {
 	return nil
 }
 
-func BalanceAt(_account string, _block int64) error {
-	bytes, err := hexutil.Decode(_account)
+// ImportKey encrypts a hex private key into a keystore file in walletDir.
+func ImportKey(privateKeyHex string, walletDir string) error {
+	// HexToECDSA rejects malformed keys and keys outside the secp256k1 range.
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(privateKeyHex), "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid private key: %v", err)
+	}
+	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+	log.Infof("Importing account %v into keystore at %s...", address.Hex(), walletDir)
+	log.Info("Enter the passphrase for this keystore file")
+	passphrase, err := util.GetPassPhrase(true)
 	if err != nil {
 		return err
 	}
+	if err := os.MkdirAll(walletDir, 0700); err != nil {
+		return util.WrapError(err, "could not create directory %s", walletDir)
+	}
+	ks := keystore.NewKeyStore(walletDir, keystore.StandardScryptN, keystore.StandardScryptP)
+	account, err := ks.ImportECDSA(privateKey, *passphrase)
+	if err != nil {
+		return util.WrapError(err, "could not import private key")
+	}
+	log.Infof("Imported Stratis account address: %v", account.Address.Hex())
+	log.Infof("Keystore file: %v", account.URL.Path)
+	return nil
+}
+
+func BalanceAt(_account string, _block int64, humanize bool) error {
 	var block *big.Int = nil
 	if _block != 0 {
 		block = big.NewInt(_block)
 	}
-	account := common.BytesToAddress(bytes)
-	bal, err := blockchain.ExecutionClient.BalanceAt(blockchain.Ctx, account, block)
+	return balanceAt(_account, block, humanize)
+}
+
+// BalanceAtDate queries the balance of an account at the most recent block produced at or before the given date.
+func BalanceAtDate(_account string, date string, humanize bool) error {
+	t, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		if t, err = time.Parse("2006-01-02", date); err != nil {
+			return fmt.Errorf("could not parse date %s: use an RFC3339 timestamp or YYYY-MM-DD", date)
+		}
+	}
+	number, err := blockchain.BlockAtTime(t)
 	if err != nil {
 		return err
+	}
+	log.Infof("Resolved date %v to block %d.", t, number)
+	return balanceAt(_account, new(big.Int).SetUint64(number), humanize)
+}
+
+// WatchBalance queries the balance of an account every interval, which is either a number of seconds or slot for the
+// slot duration of the chain, and prints the balance and the change whenever it changes until interrupted.
+func WatchBalance(_account string, interval string, humanize bool) error {
+	account, err := util.ParseAddress(_account)
+	if err != nil {
+		return err
+	}
+	var period time.Duration
+	if interval == "slot" {
+		chainTime, err := chaintime.NewFromBeaconClient()
+		if err != nil {
+			return err
+		}
+		period = chainTime.SlotDuration()
 	} else {
-		log.Infof("Balance of account %v is %v STRAX.", account, util.WeiToEther(bal))
-		return nil
+		seconds, err := strconv.Atoi(interval)
+		if err != nil || seconds < 1 {
+			return fmt.Errorf("invalid watch interval %s: use a number of seconds or slot", interval)
+		}
+		period = time.Duration(seconds) * time.Second
 	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	defer func(c context.Context) { blockchain.Ctx = c }(blockchain.Ctx)
+	log.Infof("Watching the balance of account %v every %v. Press Ctrl-C to stop.", account, period)
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	var last *big.Int
+	for {
+		// Each query gets its own timeout as the watch may run much longer than the command timeout.
+		queryCtx, cancel := context.WithTimeout(ctx, period)
+		blockchain.Ctx = queryCtx
+		bal, err := blockchain.ExecutionClient.BalanceAt(blockchain.Ctx, account, nil)
+		cancel()
+		if err != nil && ctx.Err() == nil {
+			log.Errorf("Could not get balance of account %v: %v", account, err)
+		} else if err == nil && last == nil {
+			log.Infof("Balance of account %v is %v.", account, util.FormatNative(bal, humanize))
+			last = bal
+		} else if err == nil && bal.Cmp(last) != 0 {
+			delta := new(big.Int).Sub(bal, last)
+			sign := "+"
+			if delta.Sign() < 0 {
+				sign = "-"
+				delta.Neg(delta)
+			}
+			log.Infof("Balance of account %v changed to %v (%s%v).", account, util.FormatNative(bal, humanize), sign, util.FormatNative(delta, humanize))
+			last = bal
+		}
+		select {
+		case <-ctx.Done():
+			log.Infof("Stopped watching account %v.", account)
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+type balanceResult struct {
+	Account   string   `json:"account"`
+	Block     *big.Int `json:"block,omitempty"`
+	Balance   string   `json:"balance"`
+	Formatted string   `json:"formatted"`
+}
+
+func balanceAt(_account string, block *big.Int, humanize bool) error {
+	account, err := util.ParseAddress(_account)
+	if err != nil {
+		return err
+	}
+	bal, err := blockchain.ExecutionClient.BalanceAt(blockchain.Ctx, account, block)
+	if err != nil {
+		return err
+	}
+	// The balance is in wei as a string as it can exceed the integers JSON parsers support.
+	result := &balanceResult{Account: account.Hex(), Block: block, Balance: bal.String(), Formatted: util.FormatNative(bal, humanize)}
+	return util.Render(result, func() {
+		if block != nil {
+			log.Infof("Balance of account %v at block %v is %v.", account, block, result.Formatted)
+		} else {
+			log.Infof("Balance of account %v is %v.", account, result.Formatted)
+		}
+	})
+}
+
+// BalanceDelta prints the balances of an account at two blocks and the signed change in balance between them.
+func BalanceDelta(_account string, fromBlock int64, toBlock int64) error {
+	if fromBlock < 0 || fromBlock >= toBlock {
+		return fmt.Errorf("the from block %d must be before the to block %d", fromBlock, toBlock)
+	}
+	account, err := util.ParseAddress(_account)
+	if err != nil {
+		return err
+	}
+	from, err := blockchain.ExecutionClient.BalanceAt(blockchain.Ctx, account, big.NewInt(fromBlock))
+	if err != nil {
+		return util.WrapError(err, "could not get balance at block %d", fromBlock)
+	}
+	to, err := blockchain.ExecutionClient.BalanceAt(blockchain.Ctx, account, big.NewInt(toBlock))
+	if err != nil {
+		return util.WrapError(err, "could not get balance at block %d", toBlock)
+	}
+	delta := new(big.Int).Sub(to, from)
+	sign := ""
+	if delta.Sign() > 0 {
+		sign = "+"
+	}
+	log.Infof("Balance of account %v at block %v is %v.", account, fromBlock, util.FormatNative(from, false))
+	log.Infof("Balance of account %v at block %v is %v.", account, toBlock, util.FormatNative(to, false))
+	log.Infof("Balance change from block %v to block %v is %s%v.", fromBlock, toBlock, sign, util.FormatNative(delta, false))
+	return nil
 }
 
 func AccountAddress(pubkey string) error {
@@ -75,3 +256,285 @@ func AccountAddress(pubkey string) error {
 	log.Infof("%v", hexutil.Encode(crypto.Keccak256(pkey[:])))
 	return nil
 }
+
+// Rekey decrypts a keystore file with its current passphrase and re-encrypts it with a new passphrase and strong
+// KDF parameters, backing up the original file before replacing it.
+func Rekey(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return util.WrapError(err, "could not read keystore file %s", path)
+	}
+	log.Info("Enter the current passphrase for this keystore file")
+	current, err := util.GetPassPhrase(false)
+	if err != nil {
+		return err
+	}
+	key, err := keystore.DecryptKey(data, *current)
+	if err != nil {
+		return util.WrapError(err, "could not decrypt keystore file %s", path)
+	}
+	log.Info("Enter the new passphrase for this keystore file")
+	passphrase, err := util.GetPassPhrase(true)
+	if err != nil {
+		return err
+	}
+	encrypted, err := keystore.EncryptKey(key, *passphrase, keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		return util.WrapError(err, "could not encrypt key")
+	}
+	// Make sure the new keystore can be decrypted before touching the original.
+	if verify, err := keystore.DecryptKey(encrypted, *passphrase); err != nil || verify.Address != key.Address {
+		return fmt.Errorf("could not verify the re-encrypted keystore, the original file has not been changed")
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, encrypted, 0600); err != nil {
+		return util.WrapError(err, "could not write re-encrypted keystore file")
+	}
+	backup := fmt.Sprintf("%s.%d.bak", path, time.Now().Unix())
+	if err := os.Rename(path, backup); err != nil {
+		os.Remove(tmp)
+		return util.WrapError(err, "could not back up keystore file %s", path)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return util.WrapError(err, "could not replace keystore file %s, the original is at %s", path, backup)
+	}
+	log.Infof("Re-encrypted keystore file %s for account %v. The original file was backed up to %s.", path, key.Address.Hex(), backup)
+	return nil
+}
+
+// Minimum recommended KDF parameters for keystore files.
+const (
+	minScryptN          = keystore.StandardScryptN
+	minScryptR          = 8
+	minPBKDF2Iterations = 262144
+)
+
+type keystoreKDF struct {
+	function string
+	params   map[string]any
+}
+
+// Audit inspects the KDF parameters of each keystore file in a directory without decrypting it and flags any
+// using weak settings that should be re-encrypted.
+func Audit(walletDir string) error {
+	entries, err := os.ReadDir(walletDir)
+	if err != nil {
+		return util.WrapError(err, "could not read wallet directory %s", walletDir)
+	}
+	audited, weak := 0, 0
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(walletDir, entry.Name())
+		kdf, err := readKeystoreKDF(path)
+		if err != nil {
+			log.Warnf("Skipping %s: %v", path, err)
+			continue
+		}
+		audited++
+		if problems := weakKDFProblems(kdf); len(problems) > 0 {
+			weak++
+			log.Warnf("%s: weak %s KDF parameters (%s). Recommendation: re-encrypt this keystore with stronger KDF parameters.", path, kdf.function, strings.Join(problems, ", "))
+		} else {
+			log.Infof("%s: %s KDF parameters OK. Recommendation: none.", path, kdf.function)
+		}
+	}
+	log.Infof("Audited %d keystore file(s) in %s, %d with weak KDF parameters.", audited, walletDir, weak)
+	return nil
+}
+
+// readKeystoreKDF reads the KDF function and parameters from a Web3 Secret Storage (v3) or EIP-2335 keystore file.
+func readKeystoreKDF(path string) (*keystoreKDF, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file map[string]json.RawMessage
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("not a JSON keystore file")
+	}
+	rawCrypto, exists := file["crypto"]
+	if !exists {
+		rawCrypto, exists = file["Crypto"]
+	}
+	if !exists {
+		return nil, fmt.Errorf("no crypto section found")
+	}
+	var c struct {
+		KDF       json.RawMessage `json:"kdf"`
+		KDFParams map[string]any  `json:"kdfparams"`
+	}
+	if err := json.Unmarshal(rawCrypto, &c); err != nil {
+		return nil, fmt.Errorf("malformed crypto section")
+	}
+	var function string
+	if err := json.Unmarshal(c.KDF, &function); err == nil {
+		return &keystoreKDF{function: function, params: c.KDFParams}, nil
+	}
+	// EIP-2335 keystores nest the function and params in the kdf object.
+	var kdf struct {
+		Function string         `json:"function"`
+		Params   map[string]any `json:"params"`
+	}
+	if err := json.Unmarshal(c.KDF, &kdf); err != nil || kdf.Function == "" {
+		return nil, fmt.Errorf("no KDF found")
+	}
+	return &keystoreKDF{function: kdf.Function, params: kdf.Params}, nil
+}
+
+func weakKDFProblems(kdf *keystoreKDF) []string {
+	param := func(name string) int {
+		v, _ := kdf.params[name].(float64)
+		return int(v)
+	}
+	problems := make([]string, 0)
+	switch kdf.function {
+	case "scrypt":
+		if n := param("n"); n < minScryptN {
+			problems = append(problems, fmt.Sprintf("scrypt n %d is below %d", n, minScryptN))
+		}
+		if r := param("r"); r < minScryptR {
+			problems = append(problems, fmt.Sprintf("scrypt r %d is below %d", r, minScryptR))
+		}
+		if p := param("p"); p < 1 {
+			problems = append(problems, fmt.Sprintf("scrypt p %d is below 1", p))
+		}
+	case "pbkdf2":
+		if c := param("c"); c < minPBKDF2Iterations {
+			problems = append(problems, fmt.Sprintf("pbkdf2 iterations %d is below %d", c, minPBKDF2Iterations))
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("unknown KDF %s", kdf.function))
+	}
+	return problems
+}
+
+// SendTransaction sends an amount of the native token from an account whose key is in a keystore directory, or is read
+// from stdin if keyStdin is set, to another account, and prints the transaction hash.
+func SendTransaction(fromStr string, toStr string, amountStr string, walletDir string, keyStdin bool) error {
+	from, err := util.ParseAddress(fromStr)
+	if err != nil {
+		return err
+	}
+	to, err := util.ParseAddress(toStr)
+	if err != nil {
+		return err
+	}
+	if (walletDir == "") == !keyStdin {
+		return fmt.Errorf("specify exactly one of a wallet directory or reading the key from stdin")
+	}
+	amount, err := util.ParseAmount(amountStr)
+	if err != nil {
+		return err
+	}
+
+	nonce, err := blockchain.ExecutionClient.PendingNonceAt(blockchain.Ctx, from)
+	if err != nil {
+		return util.WrapError(err, "could not get nonce of account %v", from)
+	}
+	gasPrice, err := blockchain.ExecutionClient.SuggestGasPrice(blockchain.Ctx)
+	if err != nil {
+		return util.WrapError(err, "could not get gas price")
+	}
+	// Check the amount alone first, as estimating gas for a transfer the account can't cover fails with an unclear error.
+	balance, err := blockchain.ExecutionClient.BalanceAt(blockchain.Ctx, from, nil)
+	if err != nil {
+		return util.WrapError(err, "could not get balance of account %v", from)
+	}
+	if balance.Cmp(amount) < 0 {
+		return fmt.Errorf("insufficient balance: account %v has %s but the transfer is %s", from, util.FormatNative(balance, false), util.FormatNative(amount, false))
+	}
+	gas, err := blockchain.ExecutionClient.EstimateGas(blockchain.Ctx, ethereum.CallMsg{From: from, To: &to, Value: amount})
+	if err != nil {
+		return util.WrapError(err, "could not estimate gas of transfer to %v", to)
+	}
+	cost := new(big.Int).Add(amount, new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gas)))
+	if balance.Cmp(cost) < 0 {
+		return fmt.Errorf("insufficient balance: account %v has %s but the transfer and gas cost %s", from, util.FormatNative(balance, false), util.FormatNative(cost, false))
+	}
+	chainID, err := blockchain.GetChainID()
+	if err != nil {
+		return util.WrapError(err, "could not get chain id")
+	}
+
+	tx := types.NewTx(&types.LegacyTx{Nonce: nonce, GasPrice: gasPrice, Gas: gas, To: &to, Value: amount})
+	var signed *types.Transaction
+	if keyStdin {
+		privateKey, err := ReadPrivateKeyStdin()
+		if err != nil {
+			return err
+		}
+		if address := crypto.PubkeyToAddress(privateKey.PublicKey); address != from {
+			return fmt.Errorf("the private key is for account %v not %v", address, from)
+		}
+		if signed, err = types.SignTx(tx, types.LatestSignerForChainID(chainID), privateKey); err != nil {
+			return util.WrapError(err, "could not sign transaction")
+		}
+	} else {
+		ks := keystore.NewKeyStore(walletDir, keystore.StandardScryptN, keystore.StandardScryptP)
+		account, err := ks.Find(gethaccounts.Account{Address: from})
+		if err != nil {
+			return fmt.Errorf("account %v not found in keystore at %s", from, walletDir)
+		}
+		log.Infof("Enter the passphrase for account %v", from)
+		passphrase, err := util.GetPassPhrase(false)
+		if err != nil {
+			return err
+		}
+		if signed, err = ks.SignTxWithPassphrase(account, *passphrase, tx, chainID); err != nil {
+			return util.WrapError(err, "could not sign transaction")
+		}
+	}
+
+	log.Infof("Sending %s from %v to %v with gas %d at a gas price of %v Wei...", util.FormatNative(amount, false), from, to, gas, gasPrice)
+	if err := blockchain.ExecutionClient.SendTransaction(blockchain.Ctx, signed); err != nil {
+		return util.WrapError(err, "could not send transaction")
+	}
+	log.Infof("Transaction hash: %v", signed.Hash().Hex())
+	return nil
+}
+
+// ReadPrivateKeyStdin reads a single line containing a hex private key from stdin for signing without a keystore.
+// The line is read directly into a fixed buffer rather than through a buffered reader, and the buffer and the decoded
+// key bytes are zeroed once the key has been parsed.
+func ReadPrivateKeyStdin() (*ecdsa.PrivateKey, error) {
+	log.Warn("Reading a private key from stdin exposes the key to the process environment e.g. shell history and pipes.")
+	// Room for a 0x prefix, 64 hex digits, surrounding whitespace and a line ending.
+	line := make([]byte, 128)
+	defer zero(line)
+	n := 0
+	for n < len(line) {
+		read, err := os.Stdin.Read(line[n : n+1])
+		if read == 1 && line[n] == '\n' {
+			break
+		}
+		n += read
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, util.WrapError(err, "could not read private key from stdin")
+		}
+	}
+	if n == len(line) {
+		return nil, fmt.Errorf("invalid private key: the line read from stdin is too long")
+	}
+	hexKey := bytes.TrimPrefix(bytes.TrimSpace(line[:n]), []byte("0x"))
+	key := make([]byte, hex.DecodedLen(len(hexKey)))
+	defer zero(key)
+	if _, err := hex.Decode(key, hexKey); err != nil {
+		return nil, fmt.Errorf("invalid private key: the key must be a hex string")
+	}
+	privateKey, err := crypto.ToECDSA(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %v", err)
+	}
+	return privateKey, nil
+}
+
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
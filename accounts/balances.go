@@ -0,0 +1,105 @@
+package accounts
+
+import (
+	"bufio"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/allisterb/strac/blockchain"
+	"github.com/allisterb/strac/util"
+)
+
+type accountBalance struct {
+	Account string
+	Balance *big.Int
+	Err     error
+}
+
+// Balances queries the balances of the accounts read from a file of one address per line and of the accounts given
+// individually, concurrently, and prints them in input order with the total. An account whose balance can't be
+// retrieved is reported in the table without stopping the other queries. All of the queries share the command timeout.
+func Balances(file string, accounts []string, block int64, humanize bool) error {
+	addresses := make([]string, 0, len(accounts))
+	if file != "" {
+		f, err := os.Open(file)
+		if err != nil {
+			return util.WrapError(err, "could not open file %s", file)
+		}
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			addresses = append(addresses, line)
+		}
+		if err := scanner.Err(); err != nil {
+			return util.WrapError(err, "could not read file %s", file)
+		}
+	}
+	addresses = append(addresses, accounts...)
+	if len(addresses) == 0 {
+		return fmt.Errorf("no accounts specified: use --file or --account")
+	}
+	var blockNumber *big.Int = nil
+	if block != 0 {
+		blockNumber = big.NewInt(block)
+	}
+
+	if blockNumber != nil {
+		log.Infof("Querying the balances of %d accounts at block %v...", len(addresses), blockNumber)
+	} else {
+		log.Infof("Querying the balances of %d accounts...", len(addresses))
+	}
+	results := make([]*accountBalance, len(addresses))
+	wg := new(sync.WaitGroup)
+	wg.Add(len(addresses))
+	for i, address := range addresses {
+		go func(index int, address string) {
+			defer wg.Done()
+			util.Concurrency.Acquire()
+			defer util.Concurrency.Release()
+			results[index] = accountBalanceAt(address, blockNumber)
+		}(i, address)
+	}
+	wg.Wait()
+
+	total := new(big.Int)
+	failed := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACCOUNT\tBALANCE")
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			fmt.Fprintf(w, "%s\terror: %v\n", r.Account, r.Err)
+			continue
+		}
+		total.Add(total, r.Balance)
+		fmt.Fprintf(w, "%s\t%s\n", r.Account, util.FormatNative(r.Balance, humanize))
+	}
+	fmt.Fprintf(w, "TOTAL (%d accounts)\t%s\n", len(results)-failed, util.FormatNative(total, humanize))
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("could not get the balances of %d of %d accounts", failed, len(results))
+	}
+	return nil
+}
+
+func accountBalanceAt(address string, block *big.Int) *accountBalance {
+	account, err := util.ParseAddress(address)
+	if err != nil {
+		return &accountBalance{Account: address, Err: err}
+	}
+	bal, err := blockchain.ExecutionClient.BalanceAt(blockchain.Ctx, account, block)
+	if err != nil {
+		return &accountBalance{Account: account.Hex(), Err: err}
+	}
+	return &accountBalance{Account: account.Hex(), Balance: bal}
+}
@@ -0,0 +1,90 @@
+package accounts
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/allisterb/strac/blockchain"
+	"github.com/allisterb/strac/util"
+)
+
+// EstimateBatch estimates the total gas cost of a batch of transfers read from a CSV file of recipient,amount rows
+// at the current gas price. If from is specified the gas of each transfer is estimated by the execution client,
+// otherwise each transfer is assumed to be a plain transfer costing the intrinsic transaction gas.
+func EstimateBatch(file string, from string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return util.WrapError(err, "could not open file %s", file)
+	}
+	defer f.Close()
+	var sender common.Address
+	if from != "" {
+		if sender, err = util.ParseAddress(from); err != nil {
+			return err
+		}
+	}
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = 2
+	r.TrimLeadingSpace = true
+	count := 0
+	totalGas := uint64(0)
+	totalAmount := new(big.Int)
+	for line := 1; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return util.WrapError(err, "could not read file %s", file)
+		}
+		recipient, amountStr := strings.TrimSpace(record[0]), strings.TrimSpace(record[1])
+		if !common.IsHexAddress(recipient) && line == 1 {
+			// Header row.
+			continue
+		}
+		to, err := util.ParseAddress(recipient)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", line, err)
+		}
+		amount, err := util.ParseAmount(amountStr)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", line, err)
+		}
+		gas := params.TxGas
+		if from != "" {
+			gas, err = blockchain.ExecutionClient.EstimateGas(blockchain.Ctx, ethereum.CallMsg{
+				From:  sender,
+				To:    &to,
+				Value: amount,
+			})
+			if err != nil {
+				return util.WrapError(err, "line %d: could not estimate gas of transfer to %s", line, recipient)
+			}
+		}
+		count++
+		totalGas += gas
+		totalAmount.Add(totalAmount, amount)
+	}
+	if count == 0 {
+		return fmt.Errorf("no transfers found in %s", file)
+	}
+
+	gasPrice, err := blockchain.ExecutionClient.SuggestGasPrice(blockchain.Ctx)
+	if err != nil {
+		return util.WrapError(err, "could not get gas price")
+	}
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(totalGas), gasPrice)
+	log.Infof("Transfers: %d, total amount: %s.", count, util.FormatNative(totalAmount, false))
+	log.Infof("Total gas: %d at a gas price of %v Wei.", totalGas, gasPrice)
+	log.Infof("Estimated total gas cost: %s.", util.FormatNative(cost, false))
+	log.Infof("Estimated grand total: %s.", util.FormatNative(new(big.Int).Add(totalAmount, cost), false))
+	return nil
+}
@@ -0,0 +1,100 @@
+package accounts
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	gethaccounts "github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/allisterb/strac/util"
+)
+
+// SignMessage signs a message prefixed as an Ethereum personal message (EIP-191) with the key of an account in a
+// keystore directory, or read from stdin if keyStdin is set, and prints the 65-byte signature with a v of 27 or 28.
+func SignMessage(message string, fromStr string, walletDir string, keyStdin bool) error {
+	from, err := util.ParseAddress(fromStr)
+	if err != nil {
+		return err
+	}
+	if (walletDir == "") == !keyStdin {
+		return fmt.Errorf("specify exactly one of a wallet directory or reading the key from stdin")
+	}
+	hash := gethaccounts.TextHash([]byte(message))
+	var signature []byte
+	if keyStdin {
+		privateKey, err := ReadPrivateKeyStdin()
+		if err != nil {
+			return err
+		}
+		if address := crypto.PubkeyToAddress(privateKey.PublicKey); address != from {
+			return fmt.Errorf("the private key is for account %v not %v", address, from)
+		}
+		if signature, err = crypto.Sign(hash, privateKey); err != nil {
+			return util.WrapError(err, "could not sign message")
+		}
+	} else {
+		ks := keystore.NewKeyStore(walletDir, keystore.StandardScryptN, keystore.StandardScryptP)
+		account, err := ks.Find(gethaccounts.Account{Address: from})
+		if err != nil {
+			return fmt.Errorf("account %v not found in keystore at %s", from, walletDir)
+		}
+		log.Infof("Enter the passphrase for account %v", from)
+		passphrase, err := util.GetPassPhrase(false)
+		if err != nil {
+			return err
+		}
+		if signature, err = ks.SignHashWithPassphrase(account, *passphrase, hash); err != nil {
+			return util.WrapError(err, "could not sign message")
+		}
+	}
+	// crypto.Sign returns a recovery id of 0 or 1 but personal message signatures use 27 or 28.
+	signature[crypto.RecoveryIDOffset] += 27
+	log.Infof("Signed message with account %v.", from)
+	log.Infof("Signature: %v", hexutil.Encode(signature))
+	return nil
+}
+
+// VerifyMessage recovers the signer of an Ethereum personal message (EIP-191) signature and checks it is the address.
+// Both 65-byte signatures with a v of 0, 1, 27 or 28 and 64-byte compact (EIP-2098) signatures are accepted.
+func VerifyMessage(message string, signatureHex string, addressStr string) error {
+	address, err := util.ParseAddress(addressStr)
+	if err != nil {
+		return err
+	}
+	signature, err := hexutil.Decode(strings.TrimSpace(signatureHex))
+	if err != nil {
+		return fmt.Errorf("invalid signature %s: %v", signatureHex, err)
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	switch len(signature) {
+	case crypto.SignatureLength:
+		copy(sig, signature)
+		if sig[crypto.RecoveryIDOffset] >= 27 {
+			sig[crypto.RecoveryIDOffset] -= 27
+		}
+	case crypto.SignatureLength - 1:
+		// The top bit of s holds the recovery id in a compact signature.
+		copy(sig, signature)
+		sig[crypto.RecoveryIDOffset] = sig[32] >> 7
+		sig[32] &= 0x7f
+	default:
+		return fmt.Errorf("invalid signature length %d: the signature must be 64 or 65 bytes", len(signature))
+	}
+	r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64])
+	if !crypto.ValidateSignatureValues(sig[crypto.RecoveryIDOffset], r, s, true) {
+		return fmt.Errorf("invalid signature: the signature values are out of range")
+	}
+	publicKey, err := crypto.SigToPub(gethaccounts.TextHash([]byte(message)), sig)
+	if err != nil {
+		return util.WrapError(err, "could not recover the signer of the message")
+	}
+	if signer := crypto.PubkeyToAddress(*publicKey); signer != address {
+		return fmt.Errorf("the message was signed by %v not %v", signer, address)
+	}
+	log.Infof("The signature is valid: the message was signed by %v.", address)
+	return nil
+}
@@ -0,0 +1,78 @@
+package accounts
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/pkg/errors"
+
+	"github.com/allisterb/strac/blockchain"
+	"github.com/allisterb/strac/util"
+)
+
+// erc20ABI is the subset of the ERC20 ABI needed to query token balances.
+const erc20ABI = `[
+	{"constant":true,"inputs":[{"name":"owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"type":"function"},
+	{"constant":true,"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"type":"function"},
+	{"constant":true,"inputs":[],"name":"symbol","outputs":[{"name":"","type":"string"}],"type":"function"}
+]`
+
+// TokenBalanceAt queries the balance of an account in an ERC20 token contract at the given block, or the latest block if block is 0.
+// Tokens that don't implement decimals() are assumed to have 18 decimals.
+func TokenBalanceAt(_account string, tokenContract string, _block int64, humanize bool) error {
+	account, err := util.ParseAddress(_account)
+	if err != nil {
+		return err
+	}
+	token, err := util.ParseAddress(tokenContract)
+	if err != nil {
+		return err
+	}
+	parsed, err := abi.JSON(strings.NewReader(erc20ABI))
+	if err != nil {
+		return util.WrapError(err, "could not parse ERC20 ABI")
+	}
+	contract := bind.NewBoundContract(token, parsed, blockchain.ExecutionClient, nil, nil)
+	opts := &bind.CallOpts{Context: blockchain.Ctx}
+	if _block != 0 {
+		opts.BlockNumber = big.NewInt(_block)
+	}
+
+	var out []interface{}
+	if err := contract.Call(opts, &out, "balanceOf", account); err != nil {
+		if errors.Is(err, bind.ErrNoCode) {
+			return fmt.Errorf("there is no contract at address %v", token)
+		}
+		return fmt.Errorf("token contract %v reverted or failed the balanceOf call: %v", token, err)
+	}
+	balance, ok := out[0].(*big.Int)
+	if !ok {
+		return fmt.Errorf("token contract %v returned an unexpected balanceOf result", token)
+	}
+
+	decimals := 18
+	out = nil
+	if err := contract.Call(opts, &out, "decimals"); err != nil {
+		log.Warnf("Token contract %v does not implement decimals(), assuming 18 decimals.", token)
+	} else if d, ok := out[0].(uint8); ok {
+		decimals = int(d)
+	}
+	symbol := token.Hex()
+	out = nil
+	if err := contract.Call(opts, &out, "symbol"); err == nil {
+		if s, ok := out[0].(string); ok && s != "" {
+			symbol = s
+		}
+	}
+
+	amount := util.FormatUnits(balance, decimals, humanize) + " " + symbol
+	if opts.BlockNumber != nil {
+		log.Infof("Token balance of account %v at block %v is %v.", account, opts.BlockNumber, amount)
+	} else {
+		log.Infof("Token balance of account %v is %v.", account, amount)
+	}
+	return nil
+}
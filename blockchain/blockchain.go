@@ -2,15 +2,31 @@ package blockchain
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	nethttp "net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"text/tabwriter"
 	"time"
 
 	eth2client "github.com/attestantio/go-eth2-client"
 	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
 	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/golang-jwt/jwt/v4"
 	logging "github.com/ipfs/go-log/v2"
 	"github.com/rs/zerolog"
 
@@ -19,32 +35,147 @@ import (
 
 var log = logging.Logger("strac/blockchain")
 
+// HttpUrl is the execution client endpoint connected to at startup. Use CurrentHttpUrl for the endpoint in use.
 var HttpUrl = ""
 var BeaconHttpUrl = ""
 var ExecutionClient *ethclient.Client
 var BeaconClient eth2client.Service
 var Ctx context.Context
 
-func InitEC(httpUrl string) error {
-	client, err := ethclient.DialContext(Ctx, httpUrl)
+// Network is the name of the network strac is connected to e.g. mainnet or auroria.
+var Network = "mainnet"
+
+// NetworkConfig holds the default endpoints of a Stratis network and its chain id.
+type NetworkConfig struct {
+	HttpUrl       string
+	BeaconHttpUrl string
+	ChainID       *big.Int
+}
+
+// Networks are the Stratis networks strac can be used with.
+var Networks = map[string]NetworkConfig{
+	"mainnet": {HttpUrl: "https://rpc.stratisevm.com", BeaconHttpUrl: "http://localhost:3500", ChainID: big.NewInt(105105)},
+	"auroria": {HttpUrl: "https://auroria.rpc.stratisevm.com/", BeaconHttpUrl: "http://localhost:3500", ChainID: big.NewInt(205205)},
+}
+
+// NetworkName returns the name of the network with the chain id, or an empty string if it isn't a known network.
+func NetworkName(chainID *big.Int) string {
+	for name, network := range Networks {
+		if network.ChainID.Cmp(chainID) == 0 {
+			return name
+		}
+	}
+	return ""
+}
+
+// NoCache disables the on-disk cache of values that rarely change such as the genesis and spec.
+var NoCache = false
+
+// InitEC connects to the execution client API. httpUrls can be a comma-separated list of endpoints to fail over between,
+// in which case the first endpoint on the expected chain is used. Errors in the URLs or JWT secret, which retrying can't
+// fix, are marked with util.Permanent.
+func InitEC(httpUrls string, jwtSecretFile string, headers map[string]string, expectedChainID *big.Int) error {
+	urls := parseExecutionUrls(httpUrls)
+	if len(urls) == 0 {
+		return util.Permanent(fmt.Errorf("no execution client URL specified"))
+	}
+	ExecutionUrls = urls
+	options := []rpc.ClientOption{}
+	for k, v := range headers {
+		options = append(options, rpc.WithHeader(k, v))
+	}
+	if jwtSecretFile != "" {
+		secret, err := readJWTSecret(jwtSecretFile)
+		if err != nil {
+			return util.Permanent(err)
+		}
+		options = append(options, rpc.WithHTTPAuth(newJWTAuth(secret)))
+	}
+	if len(urls) > 1 {
+		return initFailoverEC(urls, expectedChainID, options)
+	}
+	rpcClient, err := rpc.DialOptions(Ctx, urls[0], options...)
 	if err != nil {
 		return fmt.Errorf("error connecting to node: %v", err)
 	}
-	HttpUrl = httpUrl
-	ExecutionClient = client
+	HttpUrl, executionTransport = urls[0], nil
+	ExecutionClient = ethclient.NewClient(rpcClient)
 	return nil
 }
 
-func InitCC(beaconHttpUrl string, timeout int) error {
+// IsWebsocketUrl returns true if the execution client endpoint is a WebSocket endpoint, which supports subscriptions.
+func IsWebsocketUrl(u string) bool {
+	u = strings.ToLower(u)
+	return strings.HasPrefix(u, "ws://") || strings.HasPrefix(u, "wss://")
+}
+
+// IsAuthError returns true if the error indicates the node rejected the request's authentication.
+func IsAuthError(err error) bool {
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == nethttp.StatusUnauthorized || httpErr.StatusCode == nethttp.StatusForbidden
+	}
+	return false
+}
+
+// newJWTAuth creates an HTTP authentication provider that sets a freshly issued HS256 token on each request, as the
+// engine API expects. It matches go-ethereum's node.NewJWTAuth, which can't be used as the node package doesn't link
+// with recent Go versions.
+func newJWTAuth(secret [32]byte) rpc.HTTPAuth {
+	return func(h nethttp.Header) error {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"iat": &jwt.NumericDate{Time: time.Now()},
+		})
+		signed, err := token.SignedString(secret[:])
+		if err != nil {
+			return util.WrapError(err, "could not create JWT token")
+		}
+		h.Set("Authorization", "Bearer "+signed)
+		return nil
+	}
+}
+
+func readJWTSecret(path string) ([32]byte, error) {
+	secret := [32]byte{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return secret, util.WrapError(err, "could not read JWT secret file %s", path)
+	}
+	hex := strings.TrimPrefix(strings.TrimSpace(string(data)), "0x")
+	b, err := hexutil.Decode("0x" + hex)
+	if err != nil {
+		return secret, util.WrapError(err, "JWT secret in file %s is not a valid hex string", path)
+	}
+	if len(b) != 32 {
+		return secret, fmt.Errorf("JWT secret in file %s must be 32 bytes but is %d bytes", path, len(b))
+	}
+	copy(secret[:], b)
+	return secret, nil
+}
+
+// RequireCC returns an error if strac is not connected to a consensus client, so commands that need one fail cleanly.
+func RequireCC() error {
+	if BeaconClient == nil {
+		return fmt.Errorf("not connected to a consensus client: set --beacon-http-url to the URL of a reachable consensus client HTTP API")
+	}
+	return nil
+}
+
+func InitCC(beaconHttpUrl string, timeout int, headers map[string]string) error {
 	if BeaconClient != nil {
 		return nil
 	}
+	if err := validateBeaconUrl(beaconHttpUrl); err != nil {
+		return err
+	}
 	bclient, err := http.New(Ctx,
 		// WithAddress supplies the address of the beacon node, as a URL.
 		http.WithAddress(beaconHttpUrl),
 		// LogLevel supplies the level of logging to carry out.
 		http.WithLogLevel(zerolog.Disabled),
 		http.WithTimeout(time.Duration(timeout)*time.Second),
+		// ExtraHeaders supplies additional headers e.g. API keys to send with each request.
+		http.WithExtraHeaders(headers),
 	)
 	if err != nil {
 		return err
@@ -54,35 +185,604 @@ func InitCC(beaconHttpUrl string, timeout int) error {
 	return nil
 }
 
+// validateBeaconUrl checks the beacon node URL can be used as the base of API requests. The eth2 client appends each
+// endpoint to the full URL so a path prefix e.g. http://host:3500/beacon is supported, but a query string or fragment
+// would end up in the middle of every request URL.
+func validateBeaconUrl(beaconHttpUrl string) error {
+	u, err := url.Parse(beaconHttpUrl)
+	if err != nil {
+		return fmt.Errorf("invalid consensus client URL %s: %v", beaconHttpUrl, err)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("the consensus client URL %s must not contain a query string or fragment: use --http-header to pass API keys", beaconHttpUrl)
+	}
+	if u.Path != "" && u.Path != "/" {
+		log.Debugf("Using consensus client API path prefix %s e.g. %s.", u.Path, strings.TrimSuffix(beaconHttpUrl, "/")+"/eth/v1/node/version")
+	}
+	return nil
+}
+
 func GetChainID() (*big.Int, error) {
 	return ExecutionClient.ChainID(Ctx)
 }
+
+// blockTimes caches block timestamps sampled while searching for blocks by time.
+var blockTimes = make(map[uint64]uint64)
+var blockTimesLock sync.Mutex
+
+// BlockTimeStats holds statistics on the interval between consecutive execution blocks.
+type BlockTimeStats struct {
+	Window  int
+	Average time.Duration
+	Min     time.Duration
+	Max     time.Duration
+}
+
+// BlockTime returns the timestamp of the given block, using cached timestamps where possible.
+func BlockTime(number uint64) (uint64, error) {
+	blockTimesLock.Lock()
+	t, exists := blockTimes[number]
+	blockTimesLock.Unlock()
+	if exists {
+		return t, nil
+	}
+	header, err := ExecutionClient.HeaderByNumber(Ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return 0, util.WrapError(err, "could not get header of block %d", number)
+	}
+	blockTimesLock.Lock()
+	blockTimes[number] = header.Time
+	blockTimesLock.Unlock()
+	return header.Time, nil
+}
+
+// GetBlockTimeStats computes the average, minimum and maximum interval between the last window blocks, fetching the block headers concurrently.
+func GetBlockTimeStats(window int) (*BlockTimeStats, error) {
+	if window < 2 {
+		return nil, fmt.Errorf("the window must include at least 2 blocks")
+	}
+	latest, err := ExecutionClient.BlockNumber(Ctx)
+	if err != nil {
+		return nil, util.WrapError(err, "could not get latest block number")
+	}
+	if uint64(window) > latest+1 {
+		window = int(latest + 1)
+	}
+	first := latest + 1 - uint64(window)
+	times := make([]uint64, window)
+	errs := make([]error, window)
+	wg := new(sync.WaitGroup)
+	wg.Add(window)
+	for i := 0; i < window; i++ {
+		go func(index int) {
+			util.Concurrency.Acquire()
+			times[index], errs[index] = BlockTime(first + uint64(index))
+			util.Concurrency.Release()
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stats := &BlockTimeStats{Window: window}
+	for i := 1; i < window; i++ {
+		interval := time.Duration(times[i]-times[i-1]) * time.Second
+		if i == 1 || interval < stats.Min {
+			stats.Min = interval
+		}
+		if interval > stats.Max {
+			stats.Max = interval
+		}
+	}
+	stats.Average = time.Duration(times[window-1]-times[0]) * time.Second / time.Duration(window-1)
+	return stats, nil
+}
+
+func AvgBlockTime(window int) error {
+	stats, err := GetBlockTimeStats(window)
+	if err != nil {
+		return err
+	}
+	log.Infof("Block time over the last %d blocks: average %v, min %v, max %v.", stats.Window, stats.Average, stats.Min, stats.Max)
+	return nil
+}
+
+// BlockAtTime estimates the number of the most recent execution block produced at or before the given time
+// using a binary search over block timestamps.
+func BlockAtTime(t time.Time) (uint64, error) {
+	target := uint64(t.Unix())
+	latest, err := ExecutionClient.BlockNumber(Ctx)
+	if err != nil {
+		return 0, util.WrapError(err, "could not get latest block number")
+	}
+	genesisTime, err := BlockTime(0)
+	if err != nil {
+		return 0, err
+	}
+	if target < genesisTime {
+		return 0, fmt.Errorf("%v is before the genesis block at %v", t, time.Unix(int64(genesisTime), 0).In(util.Location))
+	}
+	lo, hi := uint64(0), latest
+	for lo < hi {
+		mid := lo + (hi-lo+1)/2
+		midTime, err := BlockTime(mid)
+		if err != nil {
+			return 0, err
+		}
+		if midTime <= target {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	log.Debugf("Resolved %v to block %d after sampling %d block timestamps.", t, lo, len(blockTimes))
+	return lo, nil
+}
+
+// WatchBlocks prints each new execution block as it arrives until interrupted. New heads are subscribed to on WebSocket
+// endpoints and polled for otherwise. If follow is true a one-line summary of each block's activity is also printed.
+func WatchBlocks(follow bool, subscribe bool) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	heads := make(chan *types.Header)
+	var errc <-chan error
+	if IsWebsocketUrl(CurrentHttpUrl()) {
+		sub, err := ExecutionClient.SubscribeNewHead(ctx, heads)
+		if errors.Is(err, rpc.ErrNotificationsUnsupported) {
+			return fmt.Errorf("the execution client endpoint %s does not support subscriptions", CurrentHttpUrl())
+		} else if err != nil {
+			return util.WrapError(err, "could not subscribe to new heads")
+		}
+		defer sub.Unsubscribe()
+		errc = sub.Err()
+		log.Infof("Subscribed to new heads at %s.", CurrentHttpUrl())
+	} else if subscribe {
+		return fmt.Errorf("the execution client endpoint %s is HTTP-only and does not support subscriptions: use a ws:// or wss:// endpoint with --http-url, or omit --subscribe to poll for new blocks", CurrentHttpUrl())
+	} else {
+		log.Infof("Endpoint %s does not support subscriptions, polling for new blocks.", CurrentHttpUrl())
+		go pollHeads(ctx, heads)
+	}
+
+	log.Infof("Watching for new blocks. Press Ctrl-C to stop.")
+	for {
+		select {
+		case <-ctx.Done():
+			log.Infof("Stopped watching blocks.")
+			return nil
+		case err := <-errc:
+			return util.WrapError(err, "head subscription failed")
+		case header := <-heads:
+			if !follow {
+				log.Infof("Block %v: %v", header.Number, header.Hash().Hex())
+				continue
+			}
+			block, err := ExecutionClient.BlockByHash(ctx, header.Hash())
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Errorf("Could not get block %v: %v", header.Number, err)
+				}
+				continue
+			}
+			log.Infof("Block %v: %v, %d txs, gas used %.2f%%, base fee %s", block.Number(), block.Hash().Hex(), len(block.Transactions()), gasUsedPercent(block.GasUsed(), block.GasLimit()), baseFeeString(block.BaseFee()))
+		}
+	}
+}
+
+// pollHeads sends the header of each new block to heads by polling the latest block number until ctx is cancelled.
+func pollHeads(ctx context.Context, heads chan<- *types.Header) {
+	var next uint64
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	for {
+		number, err := ExecutionClient.BlockNumber(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Errorf("Could not get latest block number: %v", err)
+		}
+		if err == nil && next == 0 {
+			next = number
+		}
+		for ; err == nil && next <= number; next++ {
+			header, err := ExecutionClient.HeaderByNumber(ctx, new(big.Int).SetUint64(next))
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Errorf("Could not get header of block %d: %v", next, err)
+				}
+				break
+			}
+			select {
+			case heads <- header:
+			case <-ctx.Done():
+				return
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func gasUsedPercent(used uint64, limit uint64) float64 {
+	if limit == 0 {
+		return 0
+	}
+	return float64(used) * 100 / float64(limit)
+}
+
+func baseFeeString(baseFee *big.Int) string {
+	if baseFee == nil {
+		return "n/a"
+	}
+	return new(big.Float).Quo(new(big.Float).SetInt(baseFee), big.NewFloat(1e9)).Text('f', 3) + " gwei"
+}
+
+// BlockInfo prints the hash, parent hash, time, gas used and limit, base fee and number of transactions of a block.
+// A negative block number means the latest block.
+func BlockInfo(blockNumber int64) error {
+	var number *big.Int
+	if blockNumber >= 0 {
+		number = big.NewInt(blockNumber)
+	}
+	block, err := ExecutionClient.BlockByNumber(Ctx, number)
+	if err != nil {
+		return util.WrapError(err, "could not get block")
+	}
+	log.Infof("Block: %v", block.Number())
+	log.Infof("Hash: %v", block.Hash().Hex())
+	log.Infof("Parent hash: %v", block.ParentHash().Hex())
+	log.Infof("Time: %v", time.Unix(int64(block.Time()), 0).In(util.Location))
+	log.Infof("Gas used: %d of %d (%.1f%%)", block.GasUsed(), block.GasLimit(), gasUsedPercent(block.GasUsed(), block.GasLimit()))
+	log.Infof("Base fee: %s", baseFeeString(block.BaseFee()))
+	log.Infof("Transactions: %d", len(block.Transactions()))
+	return nil
+}
+
+// NonceAt prints the confirmed nonce of an account at the given block, or the latest block if block is 0, and the
+// pending nonce including transactions in the node's transaction pool when querying the latest block.
+func NonceAt(account string, block int64) error {
+	address, err := util.ParseAddress(account)
+	if err != nil {
+		return err
+	}
+	var number *big.Int
+	if block != 0 {
+		number = big.NewInt(block)
+	}
+	nonce, err := ExecutionClient.NonceAt(Ctx, address, number)
+	if err != nil {
+		return util.WrapError(err, "could not get nonce of account %v", address)
+	}
+	if number != nil {
+		log.Infof("Nonce of account %v at block %v is %d.", address, number, nonce)
+	} else {
+		log.Infof("Confirmed nonce of account %v is %d.", address, nonce)
+	}
+	if nonce == 0 {
+		log.Infof("Account %v has not sent any transactions yet.", address)
+	}
+	if number != nil {
+		return nil
+	}
+	pending, err := ExecutionClient.PendingNonceAt(Ctx, address)
+	if err != nil {
+		return util.WrapError(err, "could not get pending nonce of account %v", address)
+	}
+	log.Infof("Pending nonce of account %v is %d. Use this nonce for the next transaction.", address, pending)
+	if pending > nonce {
+		log.Infof("Account %v has %d pending transaction(s).", address, pending-nonce)
+	}
+	return nil
+}
+
+// GasInfo prints the suggested gas price and priority fee and the base fees, gas used and priority fee percentiles
+// of the most recent blocks.
+func GasInfo(blocks uint64, percentiles []float64) error {
+	if blocks == 0 {
+		return fmt.Errorf("the fee history must cover at least 1 block")
+	}
+	for i, p := range percentiles {
+		if p < 0 || p > 100 || (i > 0 && p <= percentiles[i-1]) {
+			return fmt.Errorf("percentiles must be between 0 and 100 and in increasing order")
+		}
+	}
+	gasPrice, err := ExecutionClient.SuggestGasPrice(Ctx)
+	if err != nil {
+		return util.WrapError(err, "could not get gas price")
+	}
+	log.Infof("Suggested gas price: %s", baseFeeString(gasPrice))
+	if tip, err := ExecutionClient.SuggestGasTipCap(Ctx); err != nil {
+		log.Warnf("Could not get suggested priority fee: %v", err)
+	} else {
+		log.Infof("Suggested priority fee: %s", baseFeeString(tip))
+	}
+
+	history, err := ExecutionClient.FeeHistory(Ctx, blocks, nil, percentiles)
+	if err != nil {
+		return util.WrapError(err, "could not get fee history")
+	}
+	log.Infof("Fee history of the last %d block(s):", len(history.GasUsedRatio))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := "BLOCK\tBASE FEE\tGAS USED"
+	for _, p := range percentiles {
+		header += fmt.Sprintf("\tP%g PRIORITY FEE", p)
+	}
+	fmt.Fprintln(w, header)
+	for i, ratio := range history.GasUsedRatio {
+		row := fmt.Sprintf("%v\t%s\t%.1f%%", new(big.Int).Add(history.OldestBlock, big.NewInt(int64(i))), baseFeeString(history.BaseFee[i]), ratio*100)
+		for j := range percentiles {
+			if i < len(history.Reward) && j < len(history.Reward[i]) {
+				row += "\t" + baseFeeString(history.Reward[i][j])
+			} else {
+				row += "\tn/a"
+			}
+		}
+		fmt.Fprintln(w, row)
+	}
+	if len(history.BaseFee) > len(history.GasUsedRatio) {
+		fmt.Fprintf(w, "next\t%s\t\n", baseFeeString(history.BaseFee[len(history.BaseFee)-1]))
+	}
+	return w.Flush()
+}
+
+type pingResult struct {
+	Url          string   `json:"url"`
+	ChainID      *big.Int `json:"chain_id"`
+	LatestBlock  uint64   `json:"latest_block"`
+	Syncing      bool     `json:"syncing"`
+	CurrentBlock uint64   `json:"current_block,omitempty"`
+	HighestBlock uint64   `json:"highest_block,omitempty"`
+	// Consensus is nil if no consensus client is connected.
+	Consensus *consensusPingResult `json:"consensus"`
+}
+
+type consensusPingResult struct {
+	Url          string      `json:"url"`
+	Version      string      `json:"version"`
+	HeadSlot     phase0.Slot `json:"head_slot"`
+	SyncDistance phase0.Slot `json:"sync_distance"`
+	Syncing      bool        `json:"syncing"`
+	Optimistic   bool        `json:"optimistic"`
+}
+
+// Ping checks the execution client and, if one is connected, the consensus client, and prints the chain id, latest
+// block and sync status of the execution client and the version and sync status of the consensus client.
 func Ping() error {
+	result := &pingResult{Url: CurrentHttpUrl()}
 	chainid, err := ExecutionClient.ChainID(Ctx)
 	if err != nil {
 		return fmt.Errorf("error pinging node: %v", err)
-	} else {
-		log.Infof("Chain id of node at %v is %v.", HttpUrl, chainid)
 	}
-	block, err := ExecutionClient.BlockNumber(Ctx)
-	if err != nil {
+	result.ChainID = chainid
+	if result.LatestBlock, err = ExecutionClient.BlockNumber(Ctx); err != nil {
 		return fmt.Errorf("error pinging node: %v", err)
-	} else {
-		log.Infof("Most recent block of node at %v is %v.", HttpUrl, block)
 	}
 	sp, err := ExecutionClient.SyncProgress(Ctx)
 	if err != nil {
 		return fmt.Errorf("error pinging node: %v", err)
-	} else if sp == nil {
-		log.Warnf("Could not get sync progress of node at %v.", HttpUrl)
-	} else {
-		log.Infof("Node at %v is at block %v of %v. Node synced: %v.", HttpUrl, sp.CurrentBlock, sp.HighestBlock, sp.Done())
+	} else if sp != nil {
+		// SyncProgress returns nil when the node isn't syncing.
+		result.Syncing = true
+		result.CurrentBlock, result.HighestBlock = sp.CurrentBlock, sp.HighestBlock
+	}
+	if BeaconClient != nil {
+		if result.Consensus, err = PingCC(); err != nil {
+			return err
+		}
 	}
+	return util.Render(result, func() {
+		log.Infof("Chain id of node at %v is %v.", CurrentHttpUrl(), result.ChainID)
+		log.Infof("Most recent block of node at %v is %v.", CurrentHttpUrl(), result.LatestBlock)
+		if result.Syncing {
+			log.Infof("Node at %v is at block %v of %v. Node synced: false.", CurrentHttpUrl(), result.CurrentBlock, result.HighestBlock)
+		} else {
+			log.Infof("Node at %v is synced.", CurrentHttpUrl())
+		}
+		if cc := result.Consensus; cc != nil {
+			log.Infof("Version of consensus client at %v is %s.", cc.Url, cc.Version)
+			if cc.Syncing {
+				log.Infof("Consensus client at %v is at slot %v, %v slots behind the chain head. Node synced: false.", cc.Url, cc.HeadSlot, cc.SyncDistance)
+			} else {
+				log.Infof("Consensus client at %v is synced at slot %v.", cc.Url, cc.HeadSlot)
+			}
+			if cc.Optimistic {
+				log.Warnf("Consensus client at %v is optimistic: its head has not been validated by the execution client yet.", cc.Url)
+			}
+		} else {
+			log.Infof("Consensus client is not configured or not reachable, skipping it. Set --beacon-http-url to the URL of the consensus client HTTP API to ping it too.")
+		}
+	})
+}
 
-	return nil
+// PingCC obtains the version and sync status of the consensus client.
+func PingCC() (*consensusPingResult, error) {
+	if err := RequireCC(); err != nil {
+		return nil, err
+	}
+	result := &consensusPingResult{Url: BeaconHttpUrl}
+	versionProvider, isProvider := BeaconClient.(eth2client.NodeVersionProvider)
+	if !isProvider {
+		return nil, fmt.Errorf("consensus client at %v does not support the node version API", BeaconHttpUrl)
+	}
+	version, err := versionProvider.NodeVersion(Ctx, &api.NodeVersionOpts{})
+	if err != nil {
+		return nil, fmt.Errorf("error pinging consensus client: %v", err)
+	}
+	result.Version = version.Data
+	syncingProvider, isProvider := BeaconClient.(eth2client.NodeSyncingProvider)
+	if !isProvider {
+		return nil, fmt.Errorf("consensus client at %v does not support the node syncing API", BeaconHttpUrl)
+	}
+	syncing, err := syncingProvider.NodeSyncing(Ctx, &api.NodeSyncingOpts{})
+	if err != nil {
+		return nil, fmt.Errorf("error pinging consensus client: %v", err)
+	}
+	result.HeadSlot, result.SyncDistance = syncing.Data.HeadSlot, syncing.Data.SyncDistance
+	result.Syncing, result.Optimistic = syncing.Data.IsSyncing, syncing.Data.IsOptimistic
+	return result, nil
+}
+
+// beaconCapabilities lists the beacon client provider interfaces strac knows about.
+var beaconCapabilities = []struct {
+	name      string
+	supported func(eth2client.Service) bool
+}{
+	{"Genesis", func(s eth2client.Service) bool { _, ok := s.(eth2client.GenesisProvider); return ok }},
+	{"Spec", func(s eth2client.Service) bool { _, ok := s.(eth2client.SpecProvider); return ok }},
+	{"Fork", func(s eth2client.Service) bool { _, ok := s.(eth2client.ForkProvider); return ok }},
+	{"Finality", func(s eth2client.Service) bool { _, ok := s.(eth2client.FinalityProvider); return ok }},
+	{"NodePeers", func(s eth2client.Service) bool { _, ok := s.(eth2client.NodePeersProvider); return ok }},
+	{"NodeSyncing", func(s eth2client.Service) bool { _, ok := s.(eth2client.NodeSyncingProvider); return ok }},
+	{"NodeVersion", func(s eth2client.Service) bool { _, ok := s.(eth2client.NodeVersionProvider); return ok }},
+	{"Validators", func(s eth2client.Service) bool { _, ok := s.(eth2client.ValidatorsProvider); return ok }},
+	{"ValidatorBalances", func(s eth2client.Service) bool { _, ok := s.(eth2client.ValidatorBalancesProvider); return ok }},
+	{"ProposerDuties", func(s eth2client.Service) bool { _, ok := s.(eth2client.ProposerDutiesProvider); return ok }},
+	{"AttesterDuties", func(s eth2client.Service) bool { _, ok := s.(eth2client.AttesterDutiesProvider); return ok }},
+	{"SyncCommittees", func(s eth2client.Service) bool { _, ok := s.(eth2client.SyncCommitteesProvider); return ok }},
+	{"SyncCommitteeDuties", func(s eth2client.Service) bool { _, ok := s.(eth2client.SyncCommitteeDutiesProvider); return ok }},
+	{"SignedBeaconBlock", func(s eth2client.Service) bool { _, ok := s.(eth2client.SignedBeaconBlockProvider); return ok }},
+	{"BeaconBlockHeaders", func(s eth2client.Service) bool { _, ok := s.(eth2client.BeaconBlockHeadersProvider); return ok }},
+	{"BeaconCommittees", func(s eth2client.Service) bool { _, ok := s.(eth2client.BeaconCommitteesProvider); return ok }},
+	{"BeaconState", func(s eth2client.Service) bool { _, ok := s.(eth2client.BeaconStateProvider); return ok }},
+	{"DepositContract", func(s eth2client.Service) bool { _, ok := s.(eth2client.DepositContractProvider); return ok }},
+	{"Events", func(s eth2client.Service) bool { _, ok := s.(eth2client.EventsProvider); return ok }},
+}
+
+type endpointStatus struct {
+	url     string
+	chainID *big.Int
+	block   uint64
+	synced  bool
+	err     error
+}
+
+// PingAll concurrently checks the reachability, chain id, latest block, and sync status of each execution client endpoint.
+func PingAll(urls []string, expectedChainID *big.Int, timeout time.Duration) error {
+	if len(urls) == 0 {
+		return fmt.Errorf("at least 1 URL must be specified")
+	}
+	results := make([]*endpointStatus, len(urls))
+	wg := new(sync.WaitGroup)
+	wg.Add(len(urls))
+	for i, url := range urls {
+		go func(index int, url string) {
+			defer wg.Done()
+			util.Concurrency.Acquire()
+			defer util.Concurrency.Release()
+			results[index] = pingEndpoint(url, timeout)
+		}(i, strings.TrimSpace(url))
+	}
+	wg.Wait()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "URL\tCHAIN ID\tLATEST BLOCK\tSYNCED\tSTATUS")
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Fprintf(w, "%s\t-\t-\t-\tunreachable: %v\n", r.url, r.err)
+		} else if r.chainID.Cmp(expectedChainID) != 0 {
+			fmt.Fprintf(w, "%s\t%v\t%v\t%v\tunexpected chain (expected %v)\n", r.url, r.chainID, r.block, r.synced, expectedChainID)
+		} else {
+			fmt.Fprintf(w, "%s\t%v\t%v\t%v\tok\n", r.url, r.chainID, r.block, r.synced)
+		}
+	}
+	return w.Flush()
+}
+
+func pingEndpoint(url string, timeout time.Duration) *endpointStatus {
+	status := &endpointStatus{url: url}
+	ctx, cancel := context.WithTimeout(Ctx, timeout)
+	defer cancel()
+	client, err := ethclient.DialContext(ctx, url)
+	if err != nil {
+		status.err = err
+		return status
+	}
+	defer client.Close()
+	if status.chainID, err = client.ChainID(ctx); err != nil {
+		status.err = err
+		return status
+	}
+	if status.block, err = client.BlockNumber(ctx); err != nil {
+		status.err = err
+		return status
+	}
+	sp, err := client.SyncProgress(ctx)
+	if err != nil {
+		status.err = err
+		return status
+	}
+	// A nil sync progress means the node is not syncing.
+	status.synced = sp == nil || sp.Done()
+	return status
+}
+
+type genesisInfo struct {
+	Time                time.Time `json:"time"`
+	ValidatorsRoot      string    `json:"validators_root"`
+	ForkCurrentVersion  string    `json:"fork_current_version,omitempty"`
+	ForkPreviousVersion string    `json:"fork_previous_version,omitempty"`
+}
+
+type peerSummary struct {
+	PeerID             string `json:"peer_id"`
+	LastSeenP2PAddress string `json:"last_seen_p2p_address"`
+	State              string `json:"state"`
+	Direction          string `json:"direction"`
+}
+
+type peersInfo struct {
+	States   []string       `json:"states,omitempty"`
+	Peers    []*peerSummary `json:"peers,omitempty"`
+	Inbound  int            `json:"inbound"`
+	Outbound int            `json:"outbound"`
+}
+
+type inactivityInfo struct {
+	HeadEpoch           uint64 `json:"head_epoch"`
+	FinalizedEpoch      uint64 `json:"finalized_epoch"`
+	EpochsSinceFinality uint64 `json:"epochs_since_finality"`
+	Leaking             bool   `json:"leaking"`
+	LeakEpochs          uint64 `json:"leak_epochs,omitempty"`
+	Severity            string `json:"severity,omitempty"`
+}
+
+type headConsistencyInfo struct {
+	Slot                 uint64 `json:"slot"`
+	BeaconBlockNumber    uint64 `json:"beacon_block_number"`
+	BeaconBlockHash      string `json:"beacon_block_hash"`
+	ExecutionLatestBlock uint64 `json:"execution_latest_block"`
+	ExecutionBlockHash   string `json:"execution_block_hash,omitempty"`
+	Consistent           bool   `json:"consistent"`
+	executionError       error
 }
 
-func Info(spec bool, genesis bool, peers bool) error {
+type chainInfo struct {
+	Capabilities    map[string]bool      `json:"capabilities,omitempty"`
+	Spec            map[string]any       `json:"spec,omitempty"`
+	Genesis         *genesisInfo         `json:"genesis,omitempty"`
+	Peers           *peersInfo           `json:"peers,omitempty"`
+	Inactivity      *inactivityInfo      `json:"inactivity,omitempty"`
+	HeadConsistency *headConsistencyInfo `json:"head_consistency,omitempty"`
+	spec            map[string]any
+}
+
+func Info(spec bool, genesis bool, genesisForkOnly bool, peers bool, peersDirection []string, peersState []string, peersSummary bool, capabilities bool, inactivity bool, headConsistency bool) error {
+	if err := RequireCC(); err != nil {
+		return err
+	}
+	info := &chainInfo{}
+	if capabilities {
+		info.Capabilities = make(map[string]bool, len(beaconCapabilities))
+		for _, c := range beaconCapabilities {
+			info.Capabilities[c.name] = c.supported(BeaconClient)
+		}
+	}
+
 	if spec {
 		specProvider, isProvider := BeaconClient.(eth2client.SpecProvider)
 		if !isProvider {
@@ -92,73 +792,305 @@ func Info(spec bool, genesis bool, peers bool) error {
 		if err != nil {
 			return util.WrapError(err, "failed to obtain spec")
 		}
-		log.Infof("Printing spec...")
-		for k, _v := range specResponse.Data {
-			switch v := _v.(type) {
-			case string:
-				fmt.Printf("%v: %v\n", k, v)
-			case []byte:
-				fmt.Printf("%v: %v\n", k, hexutil.Encode(v))
-			default:
-				fmt.Printf("%v: %v\n", k, v)
-			}
+		info.spec = specResponse.Data
+		info.Spec = make(map[string]any, len(specResponse.Data))
+		for k, v := range specResponse.Data {
+			info.Spec[k] = specJSONValue(v)
 		}
 	}
 
-	if genesis {
-		if provider, isProvider := BeaconClient.(eth2client.GenesisProvider); isProvider {
-			response, err := provider.Genesis(Ctx, &api.GenesisOpts{})
-			if err != nil {
-				return err
-			} else {
-				log.Infof("Genesis time: %v", response.Data.GenesisTime)
-				log.Infof("Genesis validator root: %v", response.Data.GenesisValidatorsRoot.String)
-				log.Infof("Genesis fork current version: %v", hexutil.Encode(response.Data.GenesisForkVersion[:]))
-			}
-		} else {
+	if genesis || genesisForkOnly {
+		// A single call for users who only need the genesis time and validators root.
+		provider, isProvider := BeaconClient.(eth2client.GenesisProvider)
+		if !isProvider {
 			return fmt.Errorf("could not get GenesisProvider interface")
 		}
-		if provider, isProvider := BeaconClient.(eth2client.ForkProvider); isProvider {
+		response, err := provider.Genesis(Ctx, &api.GenesisOpts{})
+		if err != nil {
+			return err
+		}
+		info.Genesis = &genesisInfo{
+			Time:           response.Data.GenesisTime.In(util.Location),
+			ValidatorsRoot: response.Data.GenesisValidatorsRoot.String(),
+		}
+		if genesis {
+			info.Genesis.ForkCurrentVersion = hexutil.Encode(response.Data.GenesisForkVersion[:])
+			provider, isProvider := BeaconClient.(eth2client.ForkProvider)
+			if !isProvider {
+				return fmt.Errorf("could not get ForkProvider interface")
+			}
 			response, err := provider.Fork(Ctx, &api.ForkOpts{State: "head"})
 			if err != nil {
 				return err
+			}
+			info.Genesis.ForkPreviousVersion = hexutil.Encode(response.Data.PreviousVersion[:])
+		}
+	}
+
+	var err error
+	if peers || peersSummary {
+		if info.Peers, err = peerInfo(peersDirection, peersState, peersSummary); err != nil {
+			return err
+		}
+	}
+
+	if inactivity {
+		if info.Inactivity, err = inactivityLeak(); err != nil {
+			return err
+		}
+	}
+
+	if headConsistency {
+		if info.HeadConsistency, err = checkHeadConsistency(); err != nil {
+			return err
+		}
+	}
+	return util.Render(info, func() { logChainInfo(info) })
+}
+
+func logChainInfo(info *chainInfo) {
+	if info.Capabilities != nil {
+		log.Infof("Beacon node capabilities at %v:", BeaconHttpUrl)
+		for _, c := range beaconCapabilities {
+			if info.Capabilities[c.name] {
+				log.Infof("  %v: supported", c.name)
 			} else {
-				log.Infof("Genesis fork previous version: %v", hexutil.Encode(response.Data.PreviousVersion[:]))
+				log.Infof("  %v: not supported", c.name)
+			}
+		}
+	}
 
+	if info.spec != nil {
+		log.Infof("Printing spec...")
+		keys := make([]string, 0, len(info.spec))
+		for k := range info.spec {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			switch v := info.spec[k].(type) {
+			case []byte, phase0.DomainType, phase0.Version:
+				fmt.Printf("%v: %v\n", k, specJSONValue(v))
+			default:
+				fmt.Printf("%v: %v\n", k, v)
 			}
+		}
+	}
+
+	if g := info.Genesis; g != nil {
+		log.Infof("Genesis time: %v", g.Time)
+		log.Infof("Genesis validator root: %v", g.ValidatorsRoot)
+		if g.ForkCurrentVersion != "" {
+			log.Infof("Genesis fork current version: %v", g.ForkCurrentVersion)
+			log.Infof("Genesis fork previous version: %v", g.ForkPreviousVersion)
+		}
+	}
+
+	if p := info.Peers; p != nil {
+		for _, peer := range p.Peers {
+			log.Infof("Peer id: %v", peer.PeerID)
+			log.Infof("Peer last seen p2p address: %v", peer.LastSeenP2PAddress)
+			log.Infof("Peer state: %v", peer.State)
+			log.Infof("Peer direction: %v\n", peer.Direction)
+		}
+		log.Infof("Inbound peers: %v", p.Inbound)
+		log.Infof("Outbound peers: %v", p.Outbound)
+		if len(p.States) == 0 {
+			log.Infof("Total peers: %v", p.Inbound+p.Outbound)
 		} else {
-			return fmt.Errorf("could not get ForkProvider interface")
+			log.Infof("Total %s peers: %v", strings.Join(p.States, " or "), p.Inbound+p.Outbound)
 		}
 	}
 
-	if peers {
-		if provider, isProvider := BeaconClient.(eth2client.NodePeersProvider); isProvider {
-			response, err := provider.NodePeers(Ctx, &api.NodePeersOpts{State: []string{"connected"}})
-			if err != nil {
-				return err
-			} else {
-				inbound := 0
-				outbound := 0
-				for _, p := range response.Data {
-					log.Infof("Peer id: %v", p.PeerID)
-					log.Infof("Peer last seen p2p address: %v", p.LastSeenP2PAddress)
-					log.Infof("Peer state: %v", p.State)
-					log.Infof("Peer direction: %v\n", p.Direction)
-					if p.Direction == "inbound" {
-						inbound++
-					} else {
-						outbound++
-					}
-				}
-				log.Infof("Inbound peers: %v", inbound)
-				log.Infof("Outbound peers: %v", outbound)
-				log.Infof("Total connected peers: %v", inbound+outbound)
-			}
+	if i := info.Inactivity; i != nil {
+		log.Infof("Head epoch: %v", i.HeadEpoch)
+		log.Infof("Finalized epoch: %v", i.FinalizedEpoch)
+		log.Infof("Epochs since finality: %v", i.EpochsSinceFinality)
+		if i.Leaking {
+			log.Warnf("Chain is in an inactivity leak for %v epoch(s), severity: %v. Validator penalties during a leak are much higher than usual.", i.LeakEpochs, i.Severity)
 		} else {
-			return fmt.Errorf("could not get GenesisProvider interface")
+			log.Infof("Chain is not in an inactivity leak.")
 		}
+	}
 
+	if h := info.HeadConsistency; h != nil {
+		log.Infof("Beacon head at slot %d has execution block %d (%v).", h.Slot, h.BeaconBlockNumber, h.BeaconBlockHash)
+		log.Infof("Execution client latest block is %d.", h.ExecutionLatestBlock)
+		switch {
+		case h.ExecutionBlockHash == "":
+			log.Warnf("Execution client does not have block %d referenced by the beacon head: %v", h.BeaconBlockNumber, h.executionError)
+		case !h.Consistent:
+			log.Warnf("Execution and consensus clients disagree on block %d: execution client has %v, beacon head references %v.", h.BeaconBlockNumber, h.ExecutionBlockHash, h.BeaconBlockHash)
+		case h.ExecutionLatestBlock > h.BeaconBlockNumber:
+			log.Infof("Execution and consensus clients agree on block %d. The execution client is %d block(s) ahead of the beacon head.", h.BeaconBlockNumber, h.ExecutionLatestBlock-h.BeaconBlockNumber)
+		default:
+			log.Infof("Execution and consensus clients agree on the head block %d.", h.BeaconBlockNumber)
+		}
 	}
+}
 
-	return nil
+// checkHeadConsistency compares the execution payload of the beacon node's head block with the execution client's view of
+// the chain. A divergence indicates one of the clients is out of sync or on a different fork.
+func checkHeadConsistency() (*headConsistencyInfo, error) {
+	provider, isProvider := BeaconClient.(eth2client.SignedBeaconBlockProvider)
+	if !isProvider {
+		return nil, fmt.Errorf("could not get signed beacon block interface")
+	}
+	response, err := provider.SignedBeaconBlock(Ctx, &api.SignedBeaconBlockOpts{Block: "head"})
+	if err != nil {
+		return nil, util.WrapError(err, "could not get beacon head block")
+	}
+	slot, err := response.Data.Slot()
+	if err != nil {
+		return nil, err
+	}
+	beaconNumber, err := response.Data.ExecutionBlockNumber()
+	if err != nil {
+		return nil, util.WrapError(err, "beacon head block at slot %d has no execution payload", slot)
+	}
+	beaconHash, err := response.Data.ExecutionBlockHash()
+	if err != nil {
+		return nil, util.WrapError(err, "beacon head block at slot %d has no execution payload", slot)
+	}
+	latest, err := ExecutionClient.BlockNumber(Ctx)
+	if err != nil {
+		return nil, util.WrapError(err, "could not get latest block number")
+	}
+	result := &headConsistencyInfo{
+		Slot:                 uint64(slot),
+		BeaconBlockNumber:    beaconNumber,
+		BeaconBlockHash:      hexutil.Encode(beaconHash[:]),
+		ExecutionLatestBlock: latest,
+	}
+	header, err := ExecutionClient.HeaderByNumber(Ctx, new(big.Int).SetUint64(beaconNumber))
+	if err != nil {
+		result.executionError = err
+		return result, nil
+	}
+	result.ExecutionBlockHash = header.Hash().Hex()
+	result.Consistent = header.Hash() == common.Hash(beaconHash)
+	return result, nil
+}
+
+// specJSONValue converts a spec value to a JSON value: byte arrays are hex strings, durations are seconds and times are
+// unix timestamps.
+func specJSONValue(v any) any {
+	switch v := v.(type) {
+	case []byte:
+		return hexutil.Encode(v)
+	case phase0.DomainType:
+		return hexutil.Encode(v[:])
+	case phase0.Version:
+		return hexutil.Encode(v[:])
+	case time.Duration:
+		return uint64(v.Seconds())
+	case time.Time:
+		return v.Unix()
+	default:
+		return v
+	}
+}
+
+// peerInfo gets the consensus client peers with one of the directions and states, sorted by last seen address, and
+// the number of inbound and outbound peers. If summaryOnly is true only the numbers of peers are returned.
+func peerInfo(directions []string, states []string, summaryOnly bool) (*peersInfo, error) {
+	provider, isProvider := BeaconClient.(eth2client.NodePeersProvider)
+	if !isProvider {
+		return nil, fmt.Errorf("could not get NodePeersProvider interface")
+	}
+	for _, d := range directions {
+		if d != "inbound" && d != "outbound" {
+			return nil, fmt.Errorf("unknown peer direction %s: the direction must be inbound or outbound", d)
+		}
+	}
+	for _, s := range states {
+		if s != "connected" && s != "connecting" && s != "disconnected" && s != "disconnecting" {
+			return nil, fmt.Errorf("unknown peer state %s: the state must be connected, connecting, disconnected or disconnecting", s)
+		}
+	}
+	response, err := provider.NodePeers(Ctx, &api.NodePeersOpts{State: states, Direction: directions})
+	if err != nil {
+		return nil, err
+	}
+	// Not every consensus client applies the filters so filter again.
+	peers := make([]*apiv1.Peer, 0, len(response.Data))
+	for _, p := range response.Data {
+		if (len(directions) == 0 || util.Contains(directions, p.Direction)) && (len(states) == 0 || util.Contains(states, p.State)) {
+			peers = append(peers, p)
+		}
+	}
+	sort.Slice(peers, func(i int, j int) bool {
+		return peers[i].LastSeenP2PAddress < peers[j].LastSeenP2PAddress
+	})
+	result := &peersInfo{States: states}
+	for _, p := range peers {
+		if !summaryOnly {
+			result.Peers = append(result.Peers, &peerSummary{PeerID: p.PeerID, LastSeenP2PAddress: p.LastSeenP2PAddress, State: p.State, Direction: p.Direction})
+		}
+		if p.Direction == "inbound" {
+			result.Inbound++
+		} else {
+			result.Outbound++
+		}
+	}
+	return result, nil
+}
+
+// inactivityLeak reports whether the chain is in an inactivity leak i.e. the finalized checkpoint is more than
+// MIN_EPOCHS_TO_INACTIVITY_PENALTY epochs behind the head, and if so how severe the leak is.
+func inactivityLeak() (*inactivityInfo, error) {
+	specProvider, isProvider := BeaconClient.(eth2client.SpecProvider)
+	if !isProvider {
+		return nil, fmt.Errorf("could not get spec interface")
+	}
+	finalityProvider, isProvider := BeaconClient.(eth2client.FinalityProvider)
+	if !isProvider {
+		return nil, fmt.Errorf("could not get finality interface")
+	}
+	headersProvider, isProvider := BeaconClient.(eth2client.BeaconBlockHeadersProvider)
+	if !isProvider {
+		return nil, fmt.Errorf("could not get beacon block headers interface")
+	}
+
+	specResponse, err := specProvider.Spec(Ctx, &api.SpecOpts{})
+	if err != nil {
+		return nil, util.WrapError(err, "failed to obtain spec")
+	}
+	slotsPerEpoch, ok := specResponse.Data["SLOTS_PER_EPOCH"].(uint64)
+	if !ok {
+		return nil, fmt.Errorf("SLOTS_PER_EPOCH not found in spec")
+	}
+	minEpochs, ok := specResponse.Data["MIN_EPOCHS_TO_INACTIVITY_PENALTY"].(uint64)
+	if !ok {
+		minEpochs = 4
+	}
+
+	headerResponse, err := headersProvider.BeaconBlockHeader(Ctx, &api.BeaconBlockHeaderOpts{Block: "head"})
+	if err != nil {
+		return nil, util.WrapError(err, "failed to obtain head block header")
+	}
+	headEpoch := uint64(headerResponse.Data.Header.Message.Slot) / slotsPerEpoch
+
+	finalityResponse, err := finalityProvider.Finality(Ctx, &api.FinalityOpts{State: "head"})
+	if err != nil {
+		return nil, util.WrapError(err, "failed to obtain finality")
+	}
+	finalizedEpoch := uint64(finalityResponse.Data.Finalized.Epoch)
+	gap := headEpoch - finalizedEpoch
+	result := &inactivityInfo{HeadEpoch: headEpoch, FinalizedEpoch: finalizedEpoch, EpochsSinceFinality: gap}
+	if gap <= minEpochs {
+		return result, nil
+	}
+
+	// Inactivity scores and so penalties grow with every epoch of the leak, so report
+	// the severity based on how long the leak has lasted.
+	result.Leaking = true
+	result.LeakEpochs = gap - minEpochs
+	result.Severity = "low"
+	if result.LeakEpochs > 64 {
+		result.Severity = "severe"
+	} else if result.LeakEpochs > 16 {
+		result.Severity = "moderate"
+	}
+	return result, nil
 }
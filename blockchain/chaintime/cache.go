@@ -0,0 +1,85 @@
+package chaintime
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/allisterb/strac/blockchain"
+	"github.com/allisterb/strac/util"
+)
+
+// CacheTTL is how long cached genesis and spec values are used before they are fetched from the beacon node again.
+var CacheTTL = 24 * time.Hour
+
+// cachedChainTime is the on-disk form of the genesis and spec values needed to construct a ChainTime.
+type cachedChainTime struct {
+	Fetched                      time.Time     `json:"fetched"`
+	GenesisTime                  time.Time     `json:"genesis_time"`
+	SlotDuration                 time.Duration `json:"slot_duration"`
+	SlotsPerEpoch                uint64        `json:"slots_per_epoch"`
+	EpochsPerSyncCommitteePeriod uint64        `json:"epochs_per_sync_committee_period"`
+}
+
+// WithCacheFile sets the file used to cache the genesis and spec values. An empty path disables the cache.
+func WithCacheFile(path string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.cacheFile = path
+	})
+}
+
+// DefaultCacheFile returns the cache file for the current beacon node and network under the app data directory,
+// or an empty path if caching is disabled.
+func DefaultCacheFile() string {
+	if blockchain.NoCache {
+		return ""
+	}
+	key := sha256.Sum256([]byte(blockchain.BeaconHttpUrl + "|" + blockchain.Network))
+	return filepath.Join(util.AppData, "cache", fmt.Sprintf("chaintime-%x.json", key[:8]))
+}
+
+// readCache returns the cached chain time if the cache file exists and is fresh.
+func readCache(path string) (*ChainTime, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	cached := &cachedChainTime{}
+	if err := json.Unmarshal(data, cached); err != nil {
+		log.Debugf("Ignoring invalid chain time cache %s: %v", path, err)
+		return nil, false
+	}
+	if time.Since(cached.Fetched) > CacheTTL || cached.SlotDuration == 0 || cached.SlotsPerEpoch == 0 {
+		return nil, false
+	}
+	log.Debugf("Using cached genesis and spec values from %s.", path)
+	return &ChainTime{
+		genesisTime:                  cached.GenesisTime.In(util.Location),
+		slotDuration:                 cached.SlotDuration,
+		slotsPerEpoch:                cached.SlotsPerEpoch,
+		epochsPerSyncCommitteePeriod: cached.EpochsPerSyncCommitteePeriod,
+	}, true
+}
+
+// writeCache saves the chain time values to the cache file. Failing to write the cache is not an error.
+func writeCache(path string, s *ChainTime) {
+	data, err := json.Marshal(&cachedChainTime{
+		Fetched:                      time.Now(),
+		GenesisTime:                  s.genesisTime,
+		SlotDuration:                 s.slotDuration,
+		SlotsPerEpoch:                s.slotsPerEpoch,
+		EpochsPerSyncCommitteePeriod: s.epochsPerSyncCommitteePeriod,
+	})
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0700)
+	}
+	if err == nil {
+		err = os.WriteFile(path, data, 0600)
+	}
+	if err != nil {
+		log.Debugf("Could not write chain time cache %s: %v", path, err)
+	}
+}
@@ -1,8 +1,13 @@
 package chaintime
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	eth2client "github.com/attestantio/go-eth2-client"
@@ -27,6 +32,7 @@ type parameters struct {
 	logLevel        logging.LogLevel
 	genesisProvider eth2client.GenesisProvider
 	specProvider    eth2client.SpecProvider
+	cacheFile       string
 }
 
 // Parameter is the interface for ChainTime parameters.
@@ -90,6 +96,11 @@ func NewChainTime(params ...Parameter) (*ChainTime, error) {
 	if err != nil {
 		return nil, util.WrapError(err, "problem with parameters")
 	}
+	if parameters.cacheFile != "" {
+		if s, fresh := readCache(parameters.cacheFile); fresh {
+			return s, nil
+		}
+	}
 
 	genesisResponse, err := parameters.genesisProvider.Genesis(blockchain.Ctx, &api.GenesisOpts{})
 	if err != nil {
@@ -130,11 +141,14 @@ func NewChainTime(params ...Parameter) (*ChainTime, error) {
 	}
 
 	s := &ChainTime{
-		genesisTime:                  genesisResponse.Data.GenesisTime,
+		genesisTime:                  genesisResponse.Data.GenesisTime.In(util.Location),
 		slotDuration:                 slotDuration,
 		slotsPerEpoch:                slotsPerEpoch,
 		epochsPerSyncCommitteePeriod: epochsPerSyncCommitteePeriod,
 	}
+	if parameters.cacheFile != "" {
+		writeCache(parameters.cacheFile, s)
+	}
 
 	return s, nil
 }
@@ -220,6 +234,182 @@ func (s *ChainTime) TimestampToEpoch(timestamp time.Time) phase0.Epoch {
 	return phase0.Epoch(secondsSinceGenesis / uint64(s.slotDuration.Seconds()) / s.slotsPerEpoch)
 }
 
+// WaitUntilSlot sleeps until the given slot begins, returning immediately if it has already begun
+// or with the context's error if the context is cancelled first.
+func (s *ChainTime) WaitUntilSlot(ctx context.Context, slot phase0.Slot) error {
+	return waitUntil(ctx, s.StartOfSlot(slot))
+}
+
+// WaitUntilEpoch sleeps until the given epoch begins, returning immediately if it has already begun
+// or with the context's error if the context is cancelled first.
+func (s *ChainTime) WaitUntilEpoch(ctx context.Context, epoch phase0.Epoch) error {
+	return waitUntil(ctx, s.StartOfEpoch(epoch))
+}
+
+func waitUntil(ctx context.Context, t time.Time) error {
+	d := time.Until(t)
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// NewFromBeaconClient creates a chain time using the consensus client as the genesis and spec provider.
+func NewFromBeaconClient() (*ChainTime, error) {
+	if err := blockchain.RequireCC(); err != nil {
+		return nil, err
+	}
+	genesisProvider, isProvider := blockchain.BeaconClient.(eth2client.GenesisProvider)
+	if !isProvider {
+		return nil, fmt.Errorf("could not get genesis interface")
+	}
+	specProvider, isProvider := blockchain.BeaconClient.(eth2client.SpecProvider)
+	if !isProvider {
+		return nil, fmt.Errorf("could not get spec interface")
+	}
+	chainTime, err := NewChainTime(WithGenesisProvider(genesisProvider), WithSpecProvider(specProvider), WithCacheFile(DefaultCacheFile()))
+	if err != nil {
+		return nil, util.WrapError(err, "could not get chain time")
+	}
+	return chainTime, nil
+}
+
+// Wait blocks until the given epoch begins or the user presses Ctrl-C.
+func Wait(epochStr string) error {
+	chainTime, err := NewFromBeaconClient()
+	if err != nil {
+		return err
+	}
+	epoch, err := ParseEpoch(chainTime, epochStr)
+	if err != nil {
+		return err
+	}
+	start := chainTime.StartOfEpoch(epoch)
+	if epoch <= chainTime.CurrentEpoch() {
+		log.Infof("Epoch %d already started at %v.", epoch, start)
+		return nil
+	}
+	log.Infof("Waiting until epoch %d starts at %v (in %v)...", epoch, start, time.Until(start).Round(time.Second))
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := chainTime.WaitUntilEpoch(ctx, epoch); err != nil {
+		return fmt.Errorf("stopped waiting for epoch %d: %v", epoch, err)
+	}
+	log.Infof("Epoch %d started.", epoch)
+	return nil
+}
+
+// Time prints the start time of the given epoch or slot and how long ago it started or how long until it starts.
+func Time(epochStr string, slotStr string) error {
+	if (epochStr == "") == (slotStr == "") {
+		return fmt.Errorf("specify exactly one of an epoch or a slot")
+	}
+	chainTime, err := NewFromBeaconClient()
+	if err != nil {
+		return err
+	}
+	var name string
+	var start time.Time
+	if epochStr != "" {
+		epoch, err := ParseEpoch(chainTime, epochStr)
+		if err != nil {
+			return err
+		}
+		name, start = fmt.Sprintf("Epoch %d", epoch), chainTime.StartOfEpoch(epoch)
+	} else {
+		slot, err := ParseSlot(chainTime, slotStr)
+		if err != nil {
+			return err
+		}
+		name, start = fmt.Sprintf("Slot %d (epoch %d)", slot, chainTime.SlotToEpoch(slot)), chainTime.StartOfSlot(slot)
+	}
+	if d := time.Until(start); d > 0 {
+		log.Infof("%s starts at %v, in the future (in %v).", name, start, d.Round(time.Second))
+	} else {
+		log.Infof("%s started at %v, in the past (%v ago).", name, start, (-d).Round(time.Second))
+	}
+	return nil
+}
+
+// When prints the slot, epoch and sync committee period at an RFC3339 timestamp, or the start and end times, slots and
+// sync committee period of an epoch.
+func When(date string, epochStr string) error {
+	if (date == "") == (epochStr == "") {
+		return fmt.Errorf("specify exactly one of a date or an epoch")
+	}
+	chainTime, err := NewFromBeaconClient()
+	if err != nil {
+		return err
+	}
+	if date != "" {
+		t, err := time.Parse(time.RFC3339, date)
+		if err != nil {
+			return fmt.Errorf("could not parse date %s: use an RFC3339 timestamp e.g. 2024-01-02T15:04:05Z", date)
+		}
+		t = t.In(util.Location)
+		if t.Before(chainTime.GenesisTime()) {
+			return fmt.Errorf("%v is before the genesis time %v", t, chainTime.GenesisTime())
+		}
+		slot := chainTime.TimestampToSlot(t)
+		epoch := chainTime.TimestampToEpoch(t)
+		log.Infof("%v is in slot %d, which starts at %v.", t, slot, chainTime.StartOfSlot(slot))
+		log.Infof("Epoch: %d, starting at %v.", epoch, chainTime.StartOfEpoch(epoch))
+		log.Infof("Sync committee period: %d.", chainTime.SlotToSyncCommitteePeriod(slot))
+		return nil
+	}
+	epoch, err := ParseEpoch(chainTime, epochStr)
+	if err != nil {
+		return err
+	}
+	start := chainTime.StartOfEpoch(epoch)
+	log.Infof("Epoch %d starts at %v (Unix time %d) and ends at %v.", epoch, start, start.Unix(), chainTime.StartOfEpoch(epoch+1))
+	log.Infof("Slots: %d to %d.", chainTime.FirstSlotOfEpoch(epoch), chainTime.LastSlotOfEpoch(epoch))
+	log.Infof("Sync committee period: %d.", chainTime.SlotToSyncCommitteePeriod(chainTime.FirstSlotOfEpoch(epoch)))
+	return nil
+}
+
+// ParseSlot parses input to calculate the desired slot.
+func ParseSlot(chainTime *ChainTime, slotStr string) (phase0.Slot, error) {
+	currentSlot := chainTime.CurrentSlot()
+	switch slotStr {
+	case "", "current", "head", "-0":
+		return currentSlot, nil
+	case "last":
+		if currentSlot > 0 {
+			currentSlot--
+		}
+		return currentSlot, nil
+	case "next":
+		return currentSlot + 1, nil
+	default:
+		if base, offset, found := strings.Cut(slotStr, "+"); found && (base == "current" || base == "head") {
+			val, err := strconv.ParseUint(offset, 10, 64)
+			if err != nil {
+				return 0, errors.Wrap(err, "failed to parse slot offset")
+			}
+			return currentSlot + phase0.Slot(val), nil
+		}
+		val, err := strconv.ParseInt(slotStr, 10, 64)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to parse slot")
+		}
+		if val >= 0 {
+			return phase0.Slot(val), nil
+		}
+		if phase0.Slot(-val) > currentSlot {
+			return 0, nil
+		}
+		return currentSlot + phase0.Slot(val), nil
+	}
+}
+
 // ParseEpoch parses input to calculate the desired epoch.
 func ParseEpoch(chainTime *ChainTime, epochStr string) (phase0.Epoch, error) {
 	currentEpoch := chainTime.CurrentEpoch()
@@ -231,7 +421,16 @@ func ParseEpoch(chainTime *ChainTime, epochStr string) (phase0.Epoch, error) {
 			currentEpoch--
 		}
 		return currentEpoch, nil
+	case "next":
+		return currentEpoch + 1, nil
 	default:
+		if base, offset, found := strings.Cut(epochStr, "+"); found && (base == "current" || base == "head") {
+			val, err := strconv.ParseUint(offset, 10, 64)
+			if err != nil {
+				return 0, errors.Wrap(err, "failed to parse epoch offset")
+			}
+			return currentEpoch + phase0.Epoch(val), nil
+		}
 		val, err := strconv.ParseInt(epochStr, 10, 64)
 		if err != nil {
 			return 0, errors.Wrap(err, "failed to parse epoch")
@@ -0,0 +1,136 @@
+package blockchain
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/allisterb/strac/util"
+)
+
+type codeInfo struct {
+	Address  string `json:"address"`
+	Block    string `json:"block"`
+	Contract bool   `json:"contract"`
+	Size     int    `json:"size"`
+	CodeHash string `json:"code_hash"`
+	Code     string `json:"code,omitempty"`
+}
+
+type storageInfo struct {
+	Address string `json:"address"`
+	Block   string `json:"block"`
+	Slot    string `json:"slot"`
+	Value   string `json:"value"`
+}
+
+// CodeAt prints whether an account is a contract and the size and hash of its bytecode at a block, or the latest block
+// if block is 0. If dump is set the bytecode is printed as hex.
+func CodeAt(account string, block int64, dump bool) error {
+	address, err := util.ParseAddress(account)
+	if err != nil {
+		return err
+	}
+	number := blockNumberArg(block)
+	code, err := ExecutionClient.CodeAt(Ctx, address, number)
+	if err != nil {
+		return util.WrapError(err, "could not get code of account %v", address)
+	}
+	info := &codeInfo{
+		Address:  address.Hex(),
+		Block:    blockString(number),
+		Contract: len(code) > 0,
+		Size:     len(code),
+		CodeHash: crypto.Keccak256Hash(code).Hex(),
+	}
+	if dump {
+		info.Code = hexutil.Encode(code)
+	}
+	return util.Render(info, func() {
+		if !info.Contract {
+			log.Infof("Account %v has no code at %s and is not a contract.", info.Address, blockDescription(number))
+			return
+		}
+		log.Infof("Account %v is a contract at %s.", info.Address, blockDescription(number))
+		log.Infof("Bytecode size: %d bytes", info.Size)
+		log.Infof("Bytecode hash: %s", info.CodeHash)
+		if dump {
+			log.Infof("Bytecode: %s", info.Code)
+		}
+	})
+}
+
+// StorageAt prints the raw value of a storage slot of a contract at a block, or the latest block if block is 0. The slot
+// is a decimal number or a hex string of up to 32 bytes beginning with 0x.
+func StorageAt(account string, slotStr string, block int64) error {
+	address, err := util.ParseAddress(account)
+	if err != nil {
+		return err
+	}
+	slot, err := parseStorageSlot(slotStr)
+	if err != nil {
+		return err
+	}
+	number := blockNumberArg(block)
+	value, err := ExecutionClient.StorageAt(Ctx, address, slot, number)
+	if err != nil {
+		return util.WrapError(err, "could not get storage slot %v of account %v", slot, address)
+	}
+	info := &storageInfo{
+		Address: address.Hex(),
+		Block:   blockString(number),
+		Slot:    slot.Hex(),
+		Value:   common.BytesToHash(value).Hex(),
+	}
+	return util.Render(info, func() {
+		log.Infof("Storage slot %s of account %v at %s: %s", info.Slot, info.Address, blockDescription(number), info.Value)
+	})
+}
+
+func parseStorageSlot(s string) (common.Hash, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		b, err := hexutil.Decode("0x" + padOdd(s[2:]))
+		if err != nil || len(b) > common.HashLength {
+			return common.Hash{}, fmt.Errorf("invalid storage slot %s: the slot must be a decimal number or a hex string of up to 32 bytes beginning with 0x", s)
+		}
+		return common.BytesToHash(b), nil
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok || n.Sign() < 0 || n.BitLen() > 256 {
+		return common.Hash{}, fmt.Errorf("invalid storage slot %s: the slot must be a decimal number or a hex string of up to 32 bytes beginning with 0x", s)
+	}
+	return common.BigToHash(n), nil
+}
+
+// padOdd left-pads a hex string with an odd number of digits so slots like 0x5 can be decoded.
+func padOdd(hex string) string {
+	if len(hex)%2 == 1 {
+		return "0" + hex
+	}
+	return hex
+}
+
+func blockNumberArg(block int64) *big.Int {
+	if block == 0 {
+		return nil
+	}
+	return big.NewInt(block)
+}
+
+func blockString(number *big.Int) string {
+	if number == nil {
+		return "latest"
+	}
+	return number.String()
+}
+
+func blockDescription(number *big.Int) string {
+	if number == nil {
+		return "the latest block"
+	}
+	return fmt.Sprintf("block %v", number)
+}
@@ -0,0 +1,148 @@
+package blockchain
+
+import (
+	"fmt"
+	"math/big"
+	nethttp "net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/allisterb/strac/util"
+)
+
+// ExecutionUrls are the execution client endpoints in order of preference.
+var ExecutionUrls []string
+
+// failoverTransport sends each request to the current execution client endpoint and moves on to the next endpoint
+// when a request fails with a connection error. Errors returned by a node over HTTP do not cause a failover.
+type failoverTransport struct {
+	base    nethttp.RoundTripper
+	urls    []*url.URL
+	current int
+	lock    sync.Mutex
+}
+
+func (t *failoverTransport) RoundTrip(req *nethttp.Request) (*nethttp.Response, error) {
+	t.lock.Lock()
+	start := t.current
+	t.lock.Unlock()
+	var lastErr error
+	for i := 0; i < len(t.urls); i++ {
+		index := (start + i) % len(t.urls)
+		r := req.Clone(req.Context())
+		r.URL, r.Host = t.urls[index], t.urls[index].Host
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r.Body = body
+		}
+		resp, err := t.base.RoundTrip(r)
+		if err == nil {
+			if index != start {
+				t.lock.Lock()
+				t.current = index
+				t.lock.Unlock()
+				log.Warnf("Failed over to execution client API at %s.", t.urls[index])
+			}
+			return resp, nil
+		}
+		if req.Context().Err() != nil {
+			return nil, err
+		}
+		lastErr = err
+		log.Warnf("Execution client API at %s is unreachable: %v", t.urls[index], err)
+	}
+	return nil, lastErr
+}
+
+// URL returns the endpoint requests are currently sent to.
+func (t *failoverTransport) URL() string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.urls[t.current].String()
+}
+
+// executionTransport is the failover transport of the execution client if a list of endpoints was given.
+var executionTransport *failoverTransport
+
+// CurrentHttpUrl returns the execution client endpoint requests are currently sent to, which changes from HttpUrl when
+// the execution client fails over to another endpoint.
+func CurrentHttpUrl() string {
+	if executionTransport != nil {
+		return executionTransport.URL()
+	}
+	return HttpUrl
+}
+
+// parseExecutionUrls splits a comma-separated list of execution client endpoints.
+func parseExecutionUrls(httpUrls string) []string {
+	urls := make([]string, 0)
+	for _, u := range strings.Split(httpUrls, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// initFailoverEC checks the chain id of each execution client endpoint and creates an execution client that sends
+// requests to the first endpoint on the expected chain and fails over to the other endpoints on that chain. Endpoints
+// that are unreachable or on another chain are not used. If no endpoint is on the expected chain the first that
+// responded is used alone, so the chain id mismatch is reported.
+func initFailoverEC(urls []string, expectedChainID *big.Int, options []rpc.ClientOption) error {
+	parsed := make([]*url.URL, len(urls))
+	for i, u := range urls {
+		p, err := url.Parse(u)
+		if err != nil || (p.Scheme != "http" && p.Scheme != "https") {
+			return util.Permanent(fmt.Errorf("invalid execution client URL %s: only http and https endpoints can be used with failover", u))
+		}
+		parsed[i] = p
+	}
+	usable := make([]*url.URL, 0, len(urls))
+	var mismatched *url.URL
+	var lastErr error
+	for i, u := range urls {
+		client, err := rpc.DialOptions(Ctx, u, options...)
+		if err != nil {
+			lastErr = err
+			log.Warnf("Could not connect to execution client API at %s, not using it: %v", u, err)
+			continue
+		}
+		chainID, err := ethclient.NewClient(client).ChainID(Ctx)
+		client.Close()
+		if err != nil {
+			lastErr = err
+			log.Warnf("Execution client API at %s did not respond, not using it: %v", u, err)
+			continue
+		}
+		if expectedChainID == nil || chainID.Cmp(expectedChainID) == 0 {
+			usable = append(usable, parsed[i])
+			continue
+		}
+		log.Warnf("Execution client API at %s is on chain id %v, expected %v.", u, chainID, expectedChainID)
+		if mismatched == nil {
+			mismatched = parsed[i]
+		}
+	}
+	if len(usable) == 0 && mismatched == nil {
+		return fmt.Errorf("none of the execution client APIs responded: %v", lastErr)
+	} else if len(usable) == 0 {
+		usable = append(usable, mismatched)
+	}
+
+	transport := &failoverTransport{base: nethttp.DefaultTransport, urls: usable}
+	rpcClient, err := rpc.DialOptions(Ctx, usable[0].String(), append(options, rpc.WithHTTPClient(&nethttp.Client{Transport: transport}))...)
+	if err != nil {
+		return fmt.Errorf("error connecting to node: %v", err)
+	}
+	HttpUrl = usable[0].String()
+	executionTransport = transport
+	ExecutionClient = ethclient.NewClient(rpcClient)
+	return nil
+}
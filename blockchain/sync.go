@@ -0,0 +1,91 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	eth2client "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+)
+
+// WaitSynced checks the sync status of the execution client, and of the consensus client if it is connected, every
+// interval until both are synced, printing the sync progress of each. It returns an error if Ctx expires before the
+// node is synced.
+func WaitSynced(interval time.Duration) error {
+	ctx, stop := signal.NotifyContext(Ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	defer func(c context.Context) { Ctx = c }(Ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		log.Infof("Waiting up to %v for the node to sync, checking every %v. Press Ctrl-C to stop.", time.Until(deadline).Round(time.Second), interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		// Each check gets its own timeout so an unresponsive node doesn't use up the wait.
+		checkCtx, cancel := context.WithTimeout(ctx, interval)
+		Ctx = checkCtx
+		executionSynced := executionClientSynced()
+		consensusSynced := BeaconClient == nil || consensusClientSynced()
+		cancel()
+		if executionSynced && consensusSynced {
+			log.Infof("Node is synced.")
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("the node did not finish syncing within the timeout")
+			}
+			return fmt.Errorf("stopped waiting for the node to sync")
+		case <-ticker.C:
+		}
+	}
+}
+
+func executionClientSynced() bool {
+	sp, err := ExecutionClient.SyncProgress(Ctx)
+	if err != nil {
+		log.Warnf("Could not get sync progress of execution client at %v: %v", CurrentHttpUrl(), err)
+		return false
+	}
+	if sp == nil {
+		log.Infof("Execution client at %v is synced.", CurrentHttpUrl())
+		return true
+	}
+	progress := 0.0
+	if sp.HighestBlock > 0 {
+		progress = 100 * float64(sp.CurrentBlock) / float64(sp.HighestBlock)
+	}
+	log.Infof("Execution client at %v is at block %v of %v (%.2f%%).", CurrentHttpUrl(), sp.CurrentBlock, sp.HighestBlock, progress)
+	return false
+}
+
+func consensusClientSynced() bool {
+	provider, isProvider := BeaconClient.(eth2client.NodeSyncingProvider)
+	if !isProvider {
+		log.Warnf("Consensus client at %v does not support the node syncing API, skipping its sync status.", BeaconHttpUrl)
+		return true
+	}
+	response, err := provider.NodeSyncing(Ctx, &api.NodeSyncingOpts{})
+	if err != nil {
+		log.Warnf("Could not get sync status of consensus client at %v: %v", BeaconHttpUrl, err)
+		return false
+	}
+	state := response.Data
+	if !state.IsSyncing {
+		log.Infof("Consensus client at %v is synced at slot %v.", BeaconHttpUrl, state.HeadSlot)
+		return true
+	}
+	target := state.HeadSlot + state.SyncDistance
+	progress := 0.0
+	if target > 0 {
+		progress = 100 * float64(state.HeadSlot) / float64(target)
+	}
+	log.Infof("Consensus client at %v is at slot %v of %v (%.2f%%).", BeaconHttpUrl, state.HeadSlot, target, progress)
+	return false
+}
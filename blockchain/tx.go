@@ -0,0 +1,286 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/allisterb/strac/util"
+)
+
+type eventArg struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Indexed bool   `json:"indexed"`
+	Value   string `json:"value"`
+}
+
+type receiptLog struct {
+	Index   uint        `json:"index"`
+	Address string      `json:"address"`
+	Topics  []string    `json:"topics"`
+	Data    string      `json:"data"`
+	Event   string      `json:"event,omitempty"`
+	Args    []*eventArg `json:"args,omitempty"`
+}
+
+type receiptInfo struct {
+	Hash              string        `json:"hash"`
+	Status            string        `json:"status"`
+	Block             uint64        `json:"block"`
+	BlockHash         string        `json:"block_hash"`
+	ContractAddress   string        `json:"contract_address,omitempty"`
+	GasUsed           uint64        `json:"gas_used"`
+	CumulativeGasUsed uint64        `json:"cumulative_gas_used"`
+	EffectiveGasPrice string        `json:"effective_gas_price,omitempty"`
+	Fee               string        `json:"fee,omitempty"`
+	Logs              []*receiptLog `json:"logs"`
+	effectiveGasPrice *big.Int
+	fee               *big.Int
+}
+
+// Receipt prints the status, gas used and fee of a transaction and the logs it emitted. If abiFile is given the logs
+// of the events in the contract ABI are decoded into their named arguments.
+func Receipt(hashStr string, abiFile string) error {
+	hashBytes, err := hexutil.Decode(hashStr)
+	if err != nil || len(hashBytes) != common.HashLength {
+		return fmt.Errorf("invalid transaction hash %s: the hash must be a 32-byte hex string beginning with 0x", hashStr)
+	}
+	var contractABI *abi.ABI
+	if abiFile != "" {
+		f, err := os.Open(abiFile)
+		if err != nil {
+			return util.WrapError(err, "could not open ABI file %s", abiFile)
+		}
+		defer f.Close()
+		parsed, err := abi.JSON(f)
+		if err != nil {
+			return util.WrapError(err, "could not parse ABI file %s", abiFile)
+		}
+		contractABI = &parsed
+	}
+	hash := common.BytesToHash(hashBytes)
+	receipt, err := ExecutionClient.TransactionReceipt(Ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return fmt.Errorf("no receipt found for transaction %v: the transaction does not exist or has not been included in a block yet", hash.Hex())
+	} else if err != nil {
+		return util.WrapError(err, "could not get receipt of transaction %v", hash.Hex())
+	}
+
+	info := &receiptInfo{
+		Hash:              hash.Hex(),
+		Status:            "success",
+		Block:             receipt.BlockNumber.Uint64(),
+		BlockHash:         receipt.BlockHash.Hex(),
+		GasUsed:           receipt.GasUsed,
+		CumulativeGasUsed: receipt.CumulativeGasUsed,
+		Logs:              make([]*receiptLog, 0, len(receipt.Logs)),
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		info.Status = "failed"
+	}
+	if receipt.ContractAddress != (common.Address{}) {
+		info.ContractAddress = receipt.ContractAddress.Hex()
+	}
+	if receipt.EffectiveGasPrice != nil {
+		info.effectiveGasPrice = receipt.EffectiveGasPrice
+		info.fee = new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+		// Wei amounts are strings as they can exceed the integers JSON parsers support.
+		info.EffectiveGasPrice, info.Fee = info.effectiveGasPrice.String(), info.fee.String()
+	}
+	for _, l := range receipt.Logs {
+		rl := &receiptLog{Index: l.Index, Address: l.Address.Hex(), Topics: make([]string, 0, len(l.Topics)), Data: hexutil.Encode(l.Data)}
+		for _, topic := range l.Topics {
+			rl.Topics = append(rl.Topics, topic.Hex())
+		}
+		if contractABI != nil {
+			if err := decodeLog(contractABI, l, rl); err != nil {
+				log.Warnf("Could not decode log %d: %v", l.Index, err)
+			}
+		}
+		info.Logs = append(info.Logs, rl)
+	}
+	return util.Render(info, func() { logReceipt(info) })
+}
+
+// decodeLog decodes the arguments of a log of an event in the ABI. Logs of other events and anonymous events, which
+// have no signature topic, are left undecoded.
+func decodeLog(contractABI *abi.ABI, l *types.Log, rl *receiptLog) error {
+	if len(l.Topics) == 0 {
+		return nil
+	}
+	event, err := contractABI.EventByID(l.Topics[0])
+	if err != nil {
+		return nil
+	}
+	values := make(map[string]any)
+	if err := event.Inputs.NonIndexed().UnpackIntoMap(values, l.Data); err != nil {
+		return util.WrapError(err, "could not decode the data of event %s", event.Name)
+	}
+	indexed := make(abi.Arguments, 0)
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(values, indexed, l.Topics[1:]); err != nil {
+		return util.WrapError(err, "could not decode the topics of event %s", event.Name)
+	}
+	rl.Event = event.Sig
+	for _, input := range event.Inputs {
+		rl.Args = append(rl.Args, &eventArg{Name: input.Name, Type: input.Type.String(), Indexed: input.Indexed, Value: argString(values[input.Name])})
+	}
+	return nil
+}
+
+// argString formats a decoded argument, printing byte slices and arrays as hex.
+func argString(v any) string {
+	if b, ok := v.([]byte); ok {
+		return hexutil.Encode(b)
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+		b := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(b), rv)
+		return hexutil.Encode(b)
+	}
+	return fmt.Sprint(v)
+}
+
+type estimateInfo struct {
+	From     string `json:"from"`
+	To       string `json:"to,omitempty"`
+	Value    string `json:"value"`
+	Gas      uint64 `json:"gas"`
+	GasPrice string `json:"gas_price"`
+	Fee      string `json:"fee"`
+	Total    string `json:"total"`
+	value    *big.Int
+	gasPrice *big.Int
+	fee      *big.Int
+}
+
+// Estimate estimates the gas and fee of sending a transaction with an amount of the native token and call data from
+// an account without signing or broadcasting it. If to is empty the transaction creates a contract from the data.
+func Estimate(fromStr string, toStr string, amountStr string, dataStr string) error {
+	from, err := util.ParseAddress(fromStr)
+	if err != nil {
+		return err
+	}
+	var to *common.Address
+	if toStr != "" {
+		address, err := util.ParseAddress(toStr)
+		if err != nil {
+			return err
+		}
+		to = &address
+	}
+	amount, err := util.ParseAmount(amountStr)
+	if err != nil {
+		return err
+	}
+	var data []byte
+	if dataStr != "" {
+		if data, err = hexutil.Decode(dataStr); err != nil {
+			return fmt.Errorf("invalid data %s: the data must be a hex string beginning with 0x", dataStr)
+		}
+	}
+	if to == nil && len(data) == 0 {
+		return fmt.Errorf("specify the account to send to, or the data of the contract to create")
+	}
+
+	gas, err := ExecutionClient.EstimateGas(Ctx, ethereum.CallMsg{From: from, To: to, Value: amount, Data: data})
+	if err != nil {
+		if reason, reverted := revertReason(err); reverted {
+			return fmt.Errorf("the transaction would revert: %s", reason)
+		}
+		return util.WrapError(err, "could not estimate gas of transaction")
+	}
+	gasPrice, err := ExecutionClient.SuggestGasPrice(Ctx)
+	if err != nil {
+		return util.WrapError(err, "could not get gas price")
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(gas), gasPrice)
+	total := new(big.Int).Add(amount, fee)
+	info := &estimateInfo{From: from.Hex(), Value: amount.String(), Gas: gas, GasPrice: gasPrice.String(), Fee: fee.String(), Total: total.String(), value: amount, gasPrice: gasPrice, fee: fee}
+	if to != nil {
+		info.To = to.Hex()
+	}
+	return util.Render(info, func() {
+		if info.To != "" {
+			log.Infof("Estimate of sending %s from %v to %v:", util.FormatNative(info.value, false), info.From, info.To)
+		} else {
+			log.Infof("Estimate of creating a contract from %v with %s:", info.From, util.FormatNative(info.value, false))
+		}
+		log.Infof("Gas: %d", info.Gas)
+		log.Infof("Gas price: %s", baseFeeString(info.gasPrice))
+		log.Infof("Fee: %s", util.FormatNative(info.fee, false))
+		log.Infof("Total including the amount sent: %s", util.FormatNative(total, false))
+		log.Infof("Nothing was signed or broadcast.")
+	})
+}
+
+// revertReason obtains the reason a call reverted from the revert data the node returned with the error, or from the
+// error message if the node returned no data. It reports false if the error is not a revert.
+func revertReason(err error) (string, bool) {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if hexData, ok := dataErr.ErrorData().(string); ok {
+			data, decodeErr := hexutil.Decode(hexData)
+			if decodeErr == nil {
+				if reason, unpackErr := abi.UnpackRevert(data); unpackErr == nil {
+					return reason, true
+				}
+				if len(data) > 0 {
+					return fmt.Sprintf("custom error with data %s", hexData), true
+				}
+			}
+		}
+	}
+	if strings.Contains(err.Error(), "execution reverted") {
+		return err.Error(), true
+	}
+	return "", false
+}
+
+func logReceipt(info *receiptInfo) {
+	log.Infof("Transaction: %v", info.Hash)
+	if info.Status == "success" {
+		log.Infof("Status: success")
+	} else {
+		log.Warnf("Status: failed")
+	}
+	log.Infof("Block: %d (%v)", info.Block, info.BlockHash)
+	if info.ContractAddress != "" {
+		log.Infof("Contract created: %v", info.ContractAddress)
+	}
+	log.Infof("Gas used: %d, cumulative gas used in block: %d", info.GasUsed, info.CumulativeGasUsed)
+	if info.effectiveGasPrice != nil {
+		log.Infof("Effective gas price: %s", baseFeeString(info.effectiveGasPrice))
+		log.Infof("Fee: %s", util.FormatNative(info.fee, false))
+	}
+	log.Infof("Logs: %d", len(info.Logs))
+	for _, l := range info.Logs {
+		log.Infof("Log %d from contract %v:", l.Index, l.Address)
+		if l.Event != "" {
+			args := make([]string, 0, len(l.Args))
+			for _, arg := range l.Args {
+				args = append(args, fmt.Sprintf("%s=%s", arg.Name, arg.Value))
+			}
+			log.Infof("  Event: %s(%s)", strings.SplitN(l.Event, "(", 2)[0], strings.Join(args, ", "))
+		}
+		for i, topic := range l.Topics {
+			log.Infof("  Topic %d: %v", i, topic)
+		}
+		log.Infof("  Data: %v", l.Data)
+	}
+}
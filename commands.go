@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/alecthomas/kong"
+)
+
+// commandRequirement describes the clients and beacon provider interfaces a command relies on.
+type commandRequirement struct {
+	clients   string
+	providers []string
+}
+
+var validatorProviders = []string{"Validators", "Genesis", "Spec"}
+var perfProviders = []string{"Validators", "Genesis", "Spec", "ProposerDuties", "SignedBeaconBlock", "BeaconBlockHeaders", "AttesterDuties", "Finality"}
+
+// withValidatorProviders returns a new slice of the validator providers and the given providers, so entries never share
+// the backing array of validatorProviders.
+func withValidatorProviders(providers ...string) []string {
+	return append(append(make([]string, 0, len(validatorProviders)+len(providers)), validatorProviders...), providers...)
+}
+
+// commandRequirements is the registry of the clients needed by each command, keyed by command path.
+var commandRequirements = map[string]commandRequirement{
+	"node wait-synced":              {"both", []string{"NodeSyncing"}},
+	"ping":                          {"both", []string{"NodeVersion", "NodeSyncing"}},
+	"ping-all":                      {"execution", nil},
+	"info time":                     {"consensus", []string{"Genesis", "Spec"}},
+	"info when":                     {"consensus", []string{"Genesis", "Spec"}},
+	"info chain":                    {"both", []string{"Spec", "Genesis", "Fork", "NodePeers", "Finality", "SignedBeaconBlock", "Validators"}},
+	"account new":                   {"none", nil},
+	"account balance":               {"execution", nil},
+	"account balances":              {"execution", nil},
+	"account audit":                 {"none", nil},
+	"account rekey":                 {"none", nil},
+	"account estimate-batch":        {"execution", nil},
+	"account balance-delta":         {"execution", nil},
+	"account token-balance":         {"execution", nil},
+	"account send":                  {"execution", nil},
+	"account nonce":                 {"execution", nil},
+	"account import":                {"none", nil},
+	"account sign":                  {"none", nil},
+	"account verify":                {"none", nil},
+	"account code":                  {"execution", nil},
+	"account storage":               {"execution", nil},
+	"validator info":                {"consensus", validatorProviders},
+	"validator perf":                {"consensus", perfProviders},
+	"validator watch":               {"consensus", validatorProviders},
+	"validator proposals":           {"consensus", withValidatorProviders("ProposerDuties", "SignedBeaconBlock")},
+	"validator check-credentials":   {"consensus", validatorProviders},
+	"validator dump-set":            {"consensus", []string{"Validators"}},
+	"validator sync-rewards":        {"consensus", withValidatorProviders("SyncCommittees", "SignedBeaconBlock")},
+	"validator timeseries":          {"consensus", perfProviders},
+	"validator deposit-root":        {"none", nil},
+	"validator proposer-efficiency": {"consensus", withValidatorProviders("ProposerDuties", "SignedBeaconBlock")},
+	"validator monitor":             {"consensus", perfProviders},
+	"validator income":              {"consensus", withValidatorProviders("Finality", "SignedBeaconBlock")},
+	"validator withdrawals":         {"consensus", withValidatorProviders("SignedBeaconBlock")},
+	"validator verify-control":      {"both", validatorProviders},
+	"validator proposal-scorecard":  {"consensus", withValidatorProviders("ProposerDuties", "SignedBeaconBlock")},
+	"validator missed-rewards":      {"consensus", perfProviders},
+	"validator balance-history":     {"consensus", validatorProviders},
+	"validator list":                {"consensus", validatorProviders},
+	"validator slashings":           {"consensus", withValidatorProviders("SignedBeaconBlock")},
+	"block avg-time":                {"execution", nil},
+	"block watch":                   {"execution", nil},
+	"block info":                    {"execution", nil},
+	"tx receipt":                    {"execution", nil},
+	"tx estimate":                   {"execution", nil},
+	"gas":                           {"execution", nil},
+	"monitor finality":              {"consensus", []string{"Genesis", "Spec", "Finality"}},
+	"wait":                          {"consensus", []string{"Genesis", "Spec"}},
+	"repl":                          {"both", nil},
+	"wallet list":                   {"none", nil},
+	"wallet create":                 {"none", nil},
+	"commands":                      {"none", nil},
+	"completion":                    {"none", nil},
+}
+
+// optionalConsensusCommands use the consensus client if it is available but can run without it.
+var optionalConsensusCommands = []string{"ping", "node wait-synced", "repl"}
+
+// needsConsensusClient reports whether the command at the path relies on the consensus client.
+func needsConsensusClient(path string) bool {
+	r := commandRequirements[path]
+	return r.clients == "consensus" || r.clients == "both"
+}
+
+type CommandsCmd struct {
+}
+
+func (l *CommandsCmd) Run(ctx *kong.Context) error {
+	fmt.Println("Note: strac connects to the execution client before running any command to check the chain id.")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COMMAND\tCLIENTS\tBEACON PROVIDERS")
+	for _, node := range ctx.Model.Leaves(true) {
+		if node.Type != kong.CommandNode {
+			continue
+		}
+		path := node.Path()
+		r, exists := commandRequirements[path]
+		if !exists {
+			r = commandRequirement{clients: "unknown"}
+		}
+		providers := strings.Join(r.providers, ", ")
+		if providers == "" {
+			providers = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", path, r.clients, providers)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/kong"
+)
+
+type CompletionCmd struct {
+	Shell string `arg:"" enum:"bash,zsh,fish" help:"The shell to output the completion script for: bash, zsh or fish."`
+}
+
+// completionEntry holds the subcommands and flags that can follow a command path.
+type completionEntry struct {
+	path        string
+	subcommands []*kong.Node
+	flags       []*kong.Flag
+}
+
+func (l *CompletionCmd) Run(ctx *kong.Context) error {
+	entries := completionEntries(ctx.Model.Node, "")
+	switch l.Shell {
+	case "bash":
+		fmt.Print(bashCompletion(entries))
+	case "zsh":
+		// zsh can run bash completion functions through bashcompinit.
+		fmt.Println("autoload -U +X bashcompinit && bashcompinit")
+		fmt.Print(bashCompletion(entries))
+	case "fish":
+		fmt.Print(fishCompletion(entries))
+	default:
+		return fmt.Errorf("unknown shell %s: use bash, zsh or fish", l.Shell)
+	}
+	return nil
+}
+
+// completionEntries walks the command tree from node returning an entry for each visible command path.
+func completionEntries(node *kong.Node, path string) []*completionEntry {
+	entry := &completionEntry{path: path}
+	for _, group := range node.AllFlags(true) {
+		entry.flags = append(entry.flags, group...)
+	}
+	entries := []*completionEntry{entry}
+	for _, child := range node.Children {
+		if child.Type != kong.CommandNode || child.Hidden {
+			continue
+		}
+		entry.subcommands = append(entry.subcommands, child)
+		entries = append(entries, completionEntries(child, strings.TrimSpace(path+" "+child.Name))...)
+	}
+	return entries
+}
+
+func bashCompletion(entries []*completionEntry) string {
+	builder := strings.Builder{}
+	builder.WriteString("_strac_words() {\n    case \"$1\" in\n")
+	for _, entry := range entries {
+		words := make([]string, 0)
+		for _, command := range entry.subcommands {
+			words = append(words, command.Name)
+			words = append(words, command.Aliases...)
+		}
+		for _, flag := range entry.flags {
+			words = append(words, "--"+flag.Name)
+		}
+		builder.WriteString(fmt.Sprintf("        %q) echo %q ;;\n", entry.path, strings.Join(words, " ")))
+	}
+	builder.WriteString(`    esac
+}
+
+_strac() {
+    local cur="${COMP_WORDS[COMP_CWORD]}" path="" word next
+    for word in "${COMP_WORDS[@]:1:COMP_CWORD-1}"; do
+        [[ "$word" == -* ]] && continue
+        next="${path:+$path }$word"
+        if [[ -n "$(_strac_words "$next")" ]]; then
+            path="$next"
+        fi
+    done
+    COMPREPLY=($(compgen -W "$(_strac_words "$path")" -- "$cur"))
+}
+
+complete -F _strac strac
+`)
+	return builder.String()
+}
+
+func fishCompletion(entries []*completionEntry) string {
+	builder := strings.Builder{}
+	builder.WriteString("set -g __strac_commands")
+	for _, entry := range entries {
+		builder.WriteString(" " + fishQuote(strings.TrimSpace("strac "+entry.path)))
+	}
+	builder.WriteString(`
+
+function __strac_path
+    set -l path strac
+    for word in (commandline -opc)[2..-1]
+        string match -q -- '-*' $word; and continue
+        if contains -- "$path $word" $__strac_commands
+            set path "$path $word"
+        end
+    end
+    echo $path
+end
+
+function __strac_path_is
+    test (__strac_path) = $argv[1]
+end
+
+complete -c strac -f
+`)
+	for _, entry := range entries {
+		condition := fishQuote("__strac_path_is " + fishQuote(strings.TrimSpace("strac "+entry.path)))
+		for _, command := range entry.subcommands {
+			for _, name := range append([]string{command.Name}, command.Aliases...) {
+				builder.WriteString(fmt.Sprintf("complete -c strac -n %s -a %s -d %s\n", condition, fishQuote(name), fishQuote(command.Help)))
+			}
+		}
+		for _, flag := range entry.flags {
+			short := ""
+			if flag.Short != 0 {
+				short = fmt.Sprintf(" -s %c", flag.Short)
+			}
+			builder.WriteString(fmt.Sprintf("complete -c strac -n %s -l %s%s -d %s\n", condition, fishQuote(flag.Name), short, fishQuote(flag.Help)))
+		}
+	}
+	return builder.String()
+}
+
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
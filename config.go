@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"gopkg.in/yaml.v2"
+
+	"github.com/allisterb/strac/blockchain"
+	"github.com/allisterb/strac/util"
+)
+
+// ConfigFile supplies defaults for the global flags. Flags passed on the command line override values in the file.
+var ConfigFile = filepath.Join(util.AppData, "config.yaml")
+
+// configLoaded is set when the config file exists and has been loaded.
+var configLoaded = false
+
+type configValues struct {
+	HttpUrl       string `yaml:"http-url"`
+	BeaconHttpUrl string `yaml:"beacon-http-url"`
+	Timeout       int    `yaml:"timeout"`
+	Network       string `yaml:"network"`
+}
+
+type configFile struct {
+	configValues `yaml:",inline"`
+	Profiles     map[string]configValues `yaml:"profiles"`
+}
+
+// flagArg returns the value of a flag in the command line arguments. It is used for flags that must be known before
+// kong parses the command line e.g. the profile, so that the config file values can be resolved.
+func flagArg(args []string, flag string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == flag && i+1 < len(args) {
+			return args[i+1]
+		}
+		if strings.HasPrefix(arg, flag+"=") {
+			return strings.TrimPrefix(arg, flag+"=")
+		}
+	}
+	return ""
+}
+
+// blockNumberArgs moves a negative block number given to block info after a -- so it is parsed as the block number and
+// not as a short flag, while flags on either side of it are still parsed.
+func blockNumberArgs(args []string) []string {
+	for i := 0; i+1 < len(args) && args[i] != "--"; i++ {
+		if args[i] != "block" || args[i+1] != "info" {
+			continue
+		}
+		for j := i + 2; j < len(args) && args[j] != "--"; j++ {
+			if negativeNumber.MatchString(args[j]) {
+				scanned := append(append(make([]string, 0, len(args)+1), args[:j]...), args[j+1:]...)
+				return append(scanned, "--", args[j])
+			}
+		}
+		break
+	}
+	return args
+}
+
+var negativeNumber = regexp.MustCompile(`^-[0-9]+$`)
+
+// configLoader returns a kong configuration loader that reads the top-level values of the config file and overlays
+// the values of the given profile if any.
+func configLoader(profile string) kong.ConfigurationLoader {
+	return func(r io.Reader) (kong.Resolver, error) {
+		var c configFile
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.UnmarshalStrict(data, &c); err != nil {
+			return nil, err
+		}
+		values := c.configValues
+		if profile != "" {
+			p, ok := c.Profiles[profile]
+			if !ok {
+				names := make([]string, 0, len(c.Profiles))
+				for name := range c.Profiles {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				return nil, fmt.Errorf("profile %s is not defined. The defined profiles are: %s", profile, strings.Join(names, ", "))
+			}
+			values = values.merge(p)
+		}
+		if _, ok := blockchain.Networks[values.Network]; values.Network != "" && !ok {
+			return nil, fmt.Errorf("unknown network %s: the network must be mainnet or auroria", values.Network)
+		}
+		configLoaded = true
+		return kong.ResolverFunc(values.resolve), nil
+	}
+}
+
+func (c configValues) merge(p configValues) configValues {
+	if p.HttpUrl != "" {
+		c.HttpUrl = p.HttpUrl
+	}
+	if p.BeaconHttpUrl != "" {
+		c.BeaconHttpUrl = p.BeaconHttpUrl
+	}
+	if p.Timeout != 0 {
+		c.Timeout = p.Timeout
+	}
+	if p.Network != "" {
+		c.Network = p.Network
+	}
+	return c
+}
+
+// resolve supplies the value of a global flag from the config file. Flags of subcommands are never resolved.
+func (c configValues) resolve(_ *kong.Context, parent *kong.Path, flag *kong.Flag) (interface{}, error) {
+	if parent.App == nil {
+		return nil, nil
+	}
+	switch flag.Name {
+	case "http-url":
+		if c.HttpUrl != "" {
+			return c.HttpUrl, nil
+		}
+	case "beacon-http-url":
+		if c.BeaconHttpUrl != "" {
+			return c.BeaconHttpUrl, nil
+		}
+	case "timeout":
+		if c.Timeout != 0 {
+			return fmt.Sprint(c.Timeout), nil
+		}
+	case "network":
+		if c.Network != "" {
+			return c.Network, nil
+		}
+	}
+	return nil, nil
+}
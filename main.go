@@ -3,92 +3,451 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"math/big"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/alecthomas/kong"
+	"github.com/ethereum/go-ethereum/console/prompt"
 	logging "github.com/ipfs/go-log/v2"
 	"github.com/mbndr/figlet4go"
 
 	"github.com/allisterb/strac/accounts"
 	"github.com/allisterb/strac/blockchain"
+	"github.com/allisterb/strac/blockchain/chaintime"
 	"github.com/allisterb/strac/util"
 	"github.com/allisterb/strac/validators"
+	"github.com/allisterb/strac/wallets"
 )
 
 type PingCmd struct {
 }
 
+type PingAllCmd struct {
+	Urls            []string `help:"A comma-separated list of execution client HTTP API URLs to check."`
+	EndpointTimeout int      `help:"Timeout in seconds for checking each endpoint." default:"10"`
+}
+
+type WaitCmd struct {
+	Epoch string `help:"The epoch to wait for e.g. 1234 or head+1." default:"head+1"`
+}
+
+type MonitorFinalityCmd struct {
+	Threshold uint64 `help:"The number of epochs since finalization above which to warn that finality has stalled." default:"4"`
+}
+
+type MonitorCmd struct {
+	Finality MonitorFinalityCmd `cmd:"" help:"Watch the number of epochs since finalization and warn if finality stalls."`
+}
+
+type NodeWaitSyncedCmd struct {
+	Interval int `help:"The number of seconds between sync checks if the consensus client is not available. Otherwise the node is checked every slot." default:"12"`
+}
+
+type NodeCmd struct {
+	WaitSynced NodeWaitSyncedCmd `cmd:"" help:"Wait until the execution client, and the consensus client if available, are synced. Set the maximum time to wait in seconds with --timeout. Exits with an error if the node is not synced in time."`
+}
+
+type ReplCmd struct {
+}
+
 type InfoCmd struct {
-	Spec            bool   `help:"Print the blockchain configuration values." default:"false"`
-	Genesis         bool   `help:"Get info on the chain genesis and forks." default:"false"`
-	ValidatorPubkey string `help:"Get info on the validator with this public key." default:""`
-	Peers           bool   `help:"Get info on the validator with this public key." default:"false"`
+	Chain InfoChainCmd `cmd:"" default:"withargs" help:"Get info on the execution and consensus clients and the chain. This is the default info command."`
+	Time  InfoTimeCmd  `cmd:"" help:"Get the start time of an epoch or slot and how long ago or until it starts."`
+	When  InfoWhenCmd  `cmd:"" help:"Get the slot, epoch and sync committee period at a date, or the start time of an epoch."`
+}
+
+type InfoWhenCmd struct {
+	Date  string `help:"The RFC3339 timestamp to get the slot, epoch and sync committee period at e.g. 2024-01-02T15:04:05Z." default:""`
+	Epoch string `help:"The epoch to get the start and end times of e.g. 1234, head, last, next, head+2 or -10 for 10 epochs ago." default:""`
+}
+
+type InfoTimeCmd struct {
+	Epoch string `help:"The epoch e.g. 1234, head, last, next, head+2 or -10 for 10 epochs ago." default:""`
+	Slot  string `help:"The slot e.g. 1234, head, last, next, head+2 or -10 for 10 slots ago." default:""`
+}
+
+type InfoChainCmd struct {
+	Spec            bool     `help:"Print the blockchain configuration values." default:"false"`
+	Json            bool     `help:"Print the chain info as JSON. Same as --output json." default:"false"`
+	Genesis         bool     `help:"Get info on the chain genesis and forks." default:"false"`
+	GenesisForkOnly bool     `help:"Get only the chain genesis time and validators root with a single call." default:"false"`
+	ValidatorPubkey string   `help:"Get the index, balance, effective balance and status of the validator with this public key." default:""`
+	Peers           bool     `help:"Get info on the peers of the consensus client." default:"false"`
+	PeersDirection  []string `help:"Only list peers with these directions. Can be inbound or outbound." default:""`
+	PeersState      []string `help:"Only list peers with these states. Can be connected, connecting, disconnected or disconnecting." default:"connected"`
+	PeersSummary    bool     `help:"Print only the numbers of inbound and outbound peers without the details of each peer." default:"false"`
+	Capabilities    bool     `help:"Print which beacon client provider interfaces are supported by the connected node." default:"false"`
+	Inactivity      bool     `help:"Report whether the chain is in an inactivity leak." default:"false"`
+	HeadConsistency bool     `help:"Check that the execution and consensus clients agree on the head block." default:"false"`
 }
 
 type NewAccountCmd struct {
-	//WalletDir string `help:"The directory to create the encrypted wallet (keystore) file."`
+	WalletDir string `help:"The directory to create the encrypted wallet (keystore) file in. Omit to print the new account's private key instead." default:""`
 }
 
 type AccountAddressCmd struct {
 	PubKey string `arg:"" help:"The public key of the account."`
 }
 
+type AccountNonceCmd struct {
+	Account string `arg:"" help:"The Stratis account to query the nonce of. 40-byte hex string beginning with 0x"`
+	Block   int64  `help:"The block number to retrieve the account nonce at. Omit to query the latest block and the pending nonce." default:"0"`
+}
+
+type AccountTokenBalanceCmd struct {
+	Account  string `arg:"" help:"The Stratis account to query the token balance for. 40-byte hex string beginning with 0x"`
+	Token    string `arg:"" help:"The address of the ERC20 token contract. 40-byte hex string beginning with 0x"`
+	Block    int64  `help:"The block number to retrieve the token balance at. Omit to query the latest block." default:"0"`
+	Humanize bool   `help:"Format the balance with thousands separators." default:"false"`
+}
+
 type AccountBalanceCmd struct {
-	Account string `arg:"" help:"The Stratis account to query balance for. 40-byte hex string beginning with 0x"`
-	Block   int64  `help:"The block number to retrieve the account balance at. Omit to query the latest block." default:"0"`
+	Account  string `arg:"" help:"The Stratis account to query balance for. 40-byte hex string beginning with 0x"`
+	Block    int64  `help:"The block number to retrieve the account balance at. Omit to query the latest block." default:"0"`
+	Date     string `help:"Estimate the block at this date (RFC3339 or YYYY-MM-DD) and retrieve the account balance at that block." default:""`
+	Humanize bool   `help:"Format the balance with thousands separators." default:"false"`
+	Watch    string `help:"Re-query the balance every this many seconds, or every slot with slot, and print it whenever it changes." default:""`
+}
+
+type AccountCodeCmd struct {
+	Account string `arg:"" help:"The Stratis account to get the code of. 40-byte hex string beginning with 0x"`
+	Block   int64  `help:"The block number to retrieve the code at. Omit to query the latest block." default:"0"`
+	Dump    bool   `help:"Also print the bytecode as hex." default:"false"`
+}
+
+type AccountStorageCmd struct {
+	Account string `arg:"" help:"The contract to read the storage of. 40-byte hex string beginning with 0x"`
+	Slot    string `arg:"" help:"The storage slot to read, as a decimal number or a hex string of up to 32 bytes beginning with 0x."`
+	Block   int64  `help:"The block number to read the storage slot at. Omit to query the latest block." default:"0"`
+}
+
+type AccountBalancesCmd struct {
+	File     string   `help:"A file of accounts to query the balances of, one 40-byte hex address per line. Blank lines and lines starting with # are skipped." default:""`
+	Account  []string `help:"An account to query the balance of. Can be repeated." default:""`
+	Block    int64    `help:"The block number to retrieve the account balances at. Omit to query the latest block." default:"0"`
+	Humanize bool     `help:"Format the balances with thousands separators." default:"false"`
+}
+
+type AccountAuditCmd struct {
+	WalletDir string `help:"The directory containing the keystore files to audit." required:""`
+}
+
+type AccountRekeyCmd struct {
+	File string `arg:"" help:"The keystore file to re-encrypt."`
+}
+
+type AccountEstimateBatchCmd struct {
+	ToFile string `help:"A CSV file of recipient,amount rows." required:""`
+	From   string `help:"The sender address. If specified the gas of each transfer is estimated by the node." default:""`
+}
+
+type AccountBalanceDeltaCmd struct {
+	Account   string `arg:"" help:"The Stratis account to query the balance change for. 40-byte hex string beginning with 0x"`
+	FromBlock int64  `help:"The block to measure the balance change from." required:""`
+	ToBlock   int64  `help:"The block to measure the balance change to." required:""`
+}
+
+type AccountImportCmd struct {
+	PrivateKey string `arg:"" help:"The hex-encoded private key of the account to import."`
+	WalletDir  string `help:"The directory to create the encrypted wallet (keystore) file in." required:""`
+}
+
+type AccountSendCmd struct {
+	From      string `help:"The account to send from. 40-byte hex string beginning with 0x" required:""`
+	To        string `help:"The account to send to. 40-byte hex string beginning with 0x" required:""`
+	Amount    string `help:"The amount to send e.g. 1.5 or 1.5strax, 100gwei." required:""`
+	WalletDir string `help:"The keystore directory containing the key of the sending account." default:""`
+	KeyStdin  bool   `help:"Read the hex private key of the sending account from stdin instead of a keystore." default:"false"`
+}
+
+type AccountSignCmd struct {
+	Message   string `arg:"" help:"The message to sign."`
+	From      string `help:"The account to sign the message with. 40-byte hex string beginning with 0x" required:""`
+	WalletDir string `help:"The keystore directory containing the key of the signing account." default:""`
+	KeyStdin  bool   `help:"Read the hex private key of the signing account from stdin instead of a keystore." default:"false"`
+}
+
+type AccountVerifyCmd struct {
+	Message   string `arg:"" help:"The message that was signed."`
+	Signature string `arg:"" help:"The hex-encoded 65-byte or 64-byte compact signature."`
+	Address   string `arg:"" help:"The account expected to have signed the message. 40-byte hex string beginning with 0x"`
 }
 
 type AccountCmd struct {
-	New     NewAccountCmd     `cmd:"" help:"Create a new Stratis account."`
-	Balance AccountBalanceCmd `cmd:"" help:"Get the balance of a Stratis acount."`
+	New           NewAccountCmd           `cmd:"" help:"Create a new Stratis account."`
+	Balance       AccountBalanceCmd       `cmd:"" help:"Get the balance of a Stratis acount."`
+	Balances      AccountBalancesCmd      `cmd:"" help:"Get the balances of many Stratis accounts and their total."`
+	Audit         AccountAuditCmd         `cmd:"" help:"Check keystore files for weak KDF parameters."`
+	Rekey         AccountRekeyCmd         `cmd:"" help:"Change the passphrase of a keystore file and re-encrypt it with strong KDF parameters."`
+	EstimateBatch AccountEstimateBatchCmd `cmd:"" help:"Estimate the total gas cost of a batch of transfers."`
+	BalanceDelta  AccountBalanceDeltaCmd  `cmd:"" help:"Get the change in the balance of a Stratis account between two blocks."`
+	TokenBalance  AccountTokenBalanceCmd  `cmd:"" help:"Get the balance of a Stratis account in an ERC20 token."`
+	Import        AccountImportCmd        `cmd:"" help:"Import a private key into an encrypted keystore file."`
+	Nonce         AccountNonceCmd         `cmd:"" help:"Get the confirmed and pending nonce of a Stratis account."`
+	Send          AccountSendCmd          `cmd:"" help:"Send STRAX from a Stratis account."`
+	Sign          AccountSignCmd          `cmd:"" help:"Sign a message as an Ethereum personal message to prove ownership of a Stratis account."`
+	Verify        AccountVerifyCmd        `cmd:"" help:"Verify that a personal message signature was made by a Stratis account."`
+	Code          AccountCodeCmd          `cmd:"" help:"Get whether a Stratis account is a contract and the size and hash of its bytecode."`
+	Storage       AccountStorageCmd       `cmd:"" help:"Read a raw storage slot of a contract."`
 }
 
 type ValidatorInfoCmd struct {
-	PubKey string `help:"The public key of the validator." default:""`
+	PubKey  []string `help:"The public key(s) of the validator(s)."`
+	GroupBy string   `help:"Group the validators in the output. Can be status." default:""`
+	Json    bool     `help:"Print the validator info as JSON. Same as --output json." default:"false"`
 }
 
 type ValidatorPerfCmd struct {
-	Validators []string `arg:"" help:"A list of validator indices."`
+	Validators       []string `arg:"" help:"A list of validator indices, ranges e.g. 1000-2000:10, or 0x-prefixed public keys."`
+	StateID          string   `help:"The chain state." default:"head"`
+	Start            string   `help:"The chain epoch to start validator data collection." default:""`
+	End              string   `help:"The chain epoch to end data collection. Defaults to the most recent epoch." default:""`
+	NumEpochs        string   `help:"If either start epoch or end epoch is omitted, indicates how many epochs to collect data from the start or before the end epoch." default:""`
+	MissedBlocks     bool     `help:"Report all slots in the epoch range where no block was proposed by any validator." default:"false"`
+	FirstSlotOnly    bool     `help:"Fast mode that only checks whether each validator attested, without computing head/target correctness or inclusion distance." default:"false"`
+	AllowUnfinalized bool     `help:"Analyze epochs that are not finalized yet instead of moving the range back to the last finalized epoch." default:"false"`
+	SummaryOnly      bool     `help:"Only print the aggregate participation, missed proposals, and slashings across all validators and epochs." default:"false"`
+	Markdown         bool     `help:"Print the validator summary as a markdown table." default:"false"`
+	DumpFaults       bool     `help:"Print the results as JSON, which includes the full attestation data of each faulty validator. The same as --output json." default:"false"`
+	Format           string   `help:"The format of the validator summary. Deprecated: use --output json." enum:"text,json" default:"text" hidden:""`
+	IncludeDuties    bool     `help:"Include the proposal and sync committee duties in json and yaml output." default:"false"`
+	Csv              string   `help:"Also write the attestation counts of each slot and the participation of each validator to this CSV file." default:""`
+}
+
+type ValidatorProposalsCmd struct {
+	Validators []string `arg:"" help:"A list of validator indices, ranges e.g. 1000-2000:10, or 0x-prefixed public keys."`
+	Epoch      string   `help:"List proposals from the current epoch up to this epoch e.g. head+1 for the next epoch." default:"head+1"`
+	Start      string   `help:"List past proposals and whether the block was produced starting at this epoch instead of upcoming proposals." default:""`
+	End        string   `help:"The epoch to end listing past proposals. Defaults to the most recent epoch." default:""`
+	NumEpochs  string   `help:"If either start epoch or end epoch is omitted, indicates how many epochs to list past proposals from the start or before the end epoch." default:""`
+}
+
+type ValidatorCheckCredentialsCmd struct {
+	Validators []string `arg:"" help:"A list of validator indices, ranges e.g. 1000-2000:10, or 0x-prefixed public keys."`
+}
+
+type ValidatorDumpSetCmd struct {
+	State string `help:"The chain state to fetch the validator set at." default:"head"`
+	Out   string `help:"The JSON file to write the validator set to." default:"validators.json"`
+}
+
+type ValidatorProposerEfficiencyCmd struct {
+	Validators []string `arg:"" help:"A list of validator indices, ranges e.g. 1000-2000:10, or 0x-prefixed public keys."`
+	Start      string   `help:"The chain epoch to start proposal data collection." default:""`
+	End        string   `help:"The chain epoch to end data collection. Defaults to the most recent epoch." default:""`
+	NumEpochs  string   `help:"If either start epoch or end epoch is omitted, indicates how many epochs to collect data from the start or before the end epoch." default:""`
+}
+
+type ValidatorMonitorCmd struct {
+	Validators []string `arg:"" help:"A list of validator indices, ranges e.g. 1000-2000:10, or 0x-prefixed public keys."`
+}
+
+type ValidatorIncomeCmd struct {
+	Validators   []string `arg:"" help:"A list of validator indices, ranges e.g. 1000-2000:10, or 0x-prefixed public keys."`
+	NumEpochs    uint64   `help:"The number of most recent finalized epochs to observe rewards over." default:"10"`
+	Fiat         string   `help:"Also project the income in this fiat currency e.g. usd." default:""`
+	PriceTokenID string   `help:"The CoinGecko id of the native token used to look up its fiat price." default:"stratis"`
+	Price        float64  `help:"The fiat price of the native token to use instead of looking it up." default:"0"`
+}
+
+type ValidatorWithdrawalsCmd struct {
+	Validators []string `arg:"" help:"A list of validator indices, ranges e.g. 1000-2000:10, or 0x-prefixed public keys."`
+	Slots      uint64   `help:"The number of most recent slots to scan for withdrawals if no epoch range is given." default:"1024"`
+	Start      string   `help:"The chain epoch to start scanning blocks for withdrawals." default:""`
+	End        string   `help:"The chain epoch to end scanning. Defaults to the most recent epoch." default:""`
+	NumEpochs  string   `help:"If either start epoch or end epoch is omitted, indicates how many epochs to scan from the start or before the end epoch." default:""`
+}
+
+type ValidatorVerifyControlCmd struct {
+	Address   string `arg:"" help:"The execution address to check."`
+	Validator string `arg:"" help:"The index or public key of the validator."`
+	FromBlock uint64 `help:"The block to start scanning deposit contract logs from." default:"0"`
+	ToBlock   uint64 `help:"The block to stop scanning deposit contract logs at. Omit to scan to the latest block." default:"0"`
+}
+
+type ValidatorProposalScorecardCmd struct {
+	Validators []string `arg:"" help:"A list of validator indices, ranges e.g. 1000-2000:10, or 0x-prefixed public keys."`
+	Start      string   `help:"The chain epoch to start proposal data collection." default:""`
+	End        string   `help:"The chain epoch to end data collection. Defaults to the most recent epoch." default:""`
+	NumEpochs  string   `help:"If either start epoch or end epoch is omitted, indicates how many epochs to collect data from the start or before the end epoch." default:""`
+}
+
+type ValidatorSyncRewardsCmd struct {
+	Validators []string `arg:"" help:"A list of validator indices, ranges e.g. 1000-2000:10, or 0x-prefixed public keys."`
+	Start      string   `help:"The chain epoch to start sync committee data collection." default:""`
+	End        string   `help:"The chain epoch to end data collection. Defaults to the most recent epoch." default:""`
+	NumEpochs  string   `help:"If either start epoch or end epoch is omitted, indicates how many epochs to collect data from the start or before the end epoch." default:""`
+}
+
+type ValidatorMissedRewardsCmd struct {
+	Validators []string `arg:"" help:"A list of validator indices, ranges e.g. 1000-2000:10, or 0x-prefixed public keys."`
+	Start      string   `help:"The chain epoch to start data collection." default:""`
+	End        string   `help:"The chain epoch to end data collection. Defaults to the most recent epoch." default:""`
+	NumEpochs  string   `help:"If either start epoch or end epoch is omitted, indicates how many epochs to collect data from the start or before the end epoch." default:""`
+}
+
+type ValidatorBalanceHistoryCmd struct {
+	Validators []string `arg:"" help:"A list of validator indices, ranges e.g. 1000-2000:10, or 0x-prefixed public keys."`
+	Start      string   `help:"The chain epoch to start the balance history." default:""`
+	End        string   `help:"The chain epoch to end the balance history. Defaults to the most recent epoch." default:""`
+	NumEpochs  string   `help:"If either start epoch or end epoch is omitted, indicates how many epochs to collect data from the start or before the end epoch." default:""`
+}
+
+type ValidatorSlashingsCmd struct {
+	Validators []string `arg:"" help:"A list of validator indices, ranges e.g. 1000-2000:10, or 0x-prefixed public keys."`
+	Start      string   `help:"The chain epoch to start scanning blocks for slashings." default:""`
+	End        string   `help:"The chain epoch to end scanning. Defaults to the most recent epoch." default:""`
+	NumEpochs  string   `help:"If either start epoch or end epoch is omitted, indicates how many epochs to scan from the start or before the end epoch." default:""`
+}
+
+type ValidatorTimeseriesCmd struct {
+	Validators []string `arg:"" help:"A list of validator indices, ranges e.g. 1000-2000:10, or 0x-prefixed public keys."`
 	StateID    string   `help:"The chain state." default:"head"`
 	Start      string   `help:"The chain epoch to start validator data collection." default:""`
 	End        string   `help:"The chain epoch to end data collection. Defaults to the most recent epoch." default:""`
 	NumEpochs  string   `help:"If either start epoch or end epoch is omitted, indicates how many epochs to collect data from the start or before the end epoch." default:""`
+	Out        string   `help:"The CSV file to write the time series to." default:"series.csv"`
+}
+
+type ValidatorDepositRootCmd struct {
+	PubKey                string `help:"The public key of the validator." required:""`
+	WithdrawalCredentials string `help:"The withdrawal credentials of the validator." required:""`
+	Amount                uint64 `help:"The deposit amount in Gwei." default:"32000000000"`
+	Signature             string `help:"The deposit signature." required:""`
+}
+
+type ValidatorListCmd struct {
+	Status []string `help:"A comma-separated list of statuses to list validators with. A status can be a validator state e.g. active_ongoing, exited_slashed, or one of pending, active, exited, withdrawal or slashed. Omit to list all validators." default:""`
+	State  string   `help:"The chain state to fetch the validator set at." default:"head"`
+	Limit  int      `help:"The maximum number of validators to print. 0 prints all matching validators." default:"0"`
+}
+
+type ValidatorWatchCmd struct {
+	Validator string `arg:"" help:"The index or public key of the validator to watch."`
 }
 
 type CreateWalletCmd struct {
-	Type string `arg:"" help:"The type of wallet to create. Can be nd or hd."`
-	Name string `arg:"" help:"The name of the wallet."`
+	Type          string `arg:"" help:"The type of wallet to create. Only hd is supported, use account new or account import to add keystore files to an nd wallet."`
+	Name          string `arg:"" help:"The name of the wallet."`
+	WalletDir     string `help:"The path to create the wallet's directory of keystore files in. Defaults to ~/.struck/wallets." default:""`
+	Count         int    `help:"The number of accounts to derive." default:"1"`
+	MnemonicStdin bool   `help:"Read an existing BIP-39 mnemonic from stdin to derive the accounts from instead of generating a new one." default:"false"`
 }
 
 type ListWalletCmd struct {
-	Type      string `arg:"" help:"The type of wallet to create. Can be nd or hd."`
+	Type      string `arg:"" help:"The type of wallet to list. Can be nd or hd."`
 	Name      string `arg:"" help:"The name of the wallet."`
-	WalletDir string `arg:"" help:"The path to the wallet location."`
+	WalletDir string `arg:"" help:"The path to the wallet location. The wallet's keystore files are in a directory with the wallet's name here."`
 }
 type WalletCmd struct {
-	Create CreateWalletCmd `cmd:"createw" help:"Create a wallet."`
-	List   ListWalletCmd   `cmd:"createw" help:"Create a wallet."`
+	Create CreateWalletCmd `cmd:"" help:"Create an hd wallet and derive accounts from a BIP-39 mnemonic at m/44'/60'/0'/0/i."`
+	List   ListWalletCmd   `cmd:"" help:"List the accounts in a wallet."`
 }
 
 type ValidatorCmd struct {
-	Info ValidatorInfoCmd `cmd:"" help:"Get info on a validator identified by a public key or index."`
-	Perf ValidatorPerfCmd `cmd:"" help:"Get info on validator performance."`
+	LabelsFile string `help:"Path to a JSON or CSV file mapping validator indices or public keys to human-readable labels." default:""`
+
+	Info               ValidatorInfoCmd               `cmd:"" help:"Get info on a validator identified by a public key or index."`
+	Perf               ValidatorPerfCmd               `cmd:"" help:"Get info on validator performance."`
+	Watch              ValidatorWatchCmd              `cmd:"" help:"Watch a validator and print its status whenever it changes."`
+	Proposals          ValidatorProposalsCmd          `cmd:"" help:"List the upcoming proposal slots for validators, or past proposals and whether they were produced."`
+	CheckCredentials   ValidatorCheckCredentialsCmd   `cmd:"" help:"Check for validators with BLS (0x00) withdrawal credentials that need updating."`
+	DumpSet            ValidatorDumpSetCmd            `cmd:"" help:"Export the entire validator set at a state to a JSON file."`
+	SyncRewards        ValidatorSyncRewardsCmd        `cmd:"" help:"Get info on validator sync committee contributions and the estimated reward impact of missed contributions."`
+	Timeseries         ValidatorTimeseriesCmd         `cmd:"" help:"Export per-epoch validator participation as a time series CSV file."`
+	DepositRoot        ValidatorDepositRootCmd        `cmd:"" help:"Compute the deposit data root of a validator deposit for verification."`
+	ProposerEfficiency ValidatorProposerEfficiencyCmd `cmd:"" help:"Get info on whether validator proposals were produced and how full the blocks were."`
+	Monitor            ValidatorMonitorCmd            `cmd:"" help:"Monitor validators and report only when a validator starts or stops participating."`
+	Income             ValidatorIncomeCmd             `cmd:"" help:"Project the annual income of validators from their recent rewards."`
+	Withdrawals        ValidatorWithdrawalsCmd        `cmd:"" help:"Get info on the pending withdrawals of validators and the withdrawals made to them in an epoch range or recent slots."`
+	VerifyControl      ValidatorVerifyControlCmd      `cmd:"" help:"Check whether an execution address made the deposit for a validator or is its withdrawal address."`
+	ProposalScorecard  ValidatorProposalScorecardCmd  `cmd:"" help:"Compare the expected and actual number of blocks proposed by validators."`
+	BalanceHistory     ValidatorBalanceHistoryCmd     `cmd:"" help:"Get the balance of validators at each epoch and the change between epochs."`
+	MissedRewards      ValidatorMissedRewardsCmd      `cmd:"" help:"Estimate the rewards validators lost to missed attestations and proposals. Estimates assume the current total active balance and that all other validators performed their duties."`
+	Slashings          ValidatorSlashingsCmd          `cmd:"" help:"Check whether validators were slashed by scanning blocks for attester and proposer slashings."`
+	List               ValidatorListCmd               `cmd:"" help:"List the index, public key, balance and status of the validators with the given statuses."`
+}
+
+type BlockAvgTimeCmd struct {
+	Window int `help:"The number of most recent blocks to sample." default:"100"`
+}
+
+type BlockWatchCmd struct {
+	Follow    bool `help:"Print a summary of the transactions, gas used and base fee of each new block." default:"false"`
+	Subscribe bool `help:"Require a subscription to new heads over a ws:// or wss:// endpoint instead of falling back to polling an HTTP endpoint." default:"false"`
+}
+
+type GasCmd struct {
+	Blocks      uint64    `help:"The number of recent blocks to get the fee history of." default:"10"`
+	Percentiles []float64 `help:"The priority fee percentiles to get for each block." default:"10,50,90"`
+}
+
+type BlockInfoCmd struct {
+	Number string `arg:"" optional:"" help:"The block number. Omit or use latest or -1 for the latest block." default:"latest"`
+}
+
+type TxReceiptCmd struct {
+	Hash string `arg:"" help:"The hash of the transaction. 32-byte hex string beginning with 0x"`
+	Abi  string `help:"A contract ABI JSON file to decode the events in the transaction's logs with." default:""`
+}
+
+type TxEstimateCmd struct {
+	From   string `help:"The account to send from. 40-byte hex string beginning with 0x" required:""`
+	To     string `help:"The account or contract to send to. 40-byte hex string beginning with 0x. Omit to estimate creating a contract from the data." default:""`
+	Amount string `help:"The amount to send e.g. 1.5 or 1.5strax, 100gwei." default:"0"`
+	Data   string `help:"The hex-encoded call data or contract creation code beginning with 0x." default:""`
+}
+
+type TxCmd struct {
+	Receipt  TxReceiptCmd  `cmd:"" help:"Get the status, gas used and fee of a transaction and the logs it emitted."`
+	Estimate TxEstimateCmd `cmd:"" help:"Estimate the gas and fee of a transaction without signing or broadcasting it."`
+}
+
+type BlockCmd struct {
+	AvgTime BlockAvgTimeCmd `cmd:"" help:"Get the average, min, and max time between recent blocks."`
+	Watch   BlockWatchCmd   `cmd:"" help:"Watch for new blocks as they are produced, subscribing to new heads over a WebSocket endpoint or polling an HTTP endpoint."`
+	Info    BlockInfoCmd    `cmd:"" help:"Get info on a block."`
 }
 
 // Command-line arguments
 var CLI struct {
-	Debug         bool         `help:"Enable debug mode."`
-	Auroria       bool         `help:"Indicates the Auroria testnet should be used. Thhe execution client HTTP API will default to https://auroria.rpc.stratisevm.com/."`
-	HttpUrl       string       `help:"The URL of the Stratis execution client HTTP API." default:"https://rpc.stratisevm.com"`
-	BeaconHttpUrl string       `help:"The URL of the Stratis consensus client HTTP API." default:"http://localhost:3500"`
-	Timeout       int          `help:"Timeout for network operations." default:"120"`
-	Ping          PingCmd      `cmd:"" help:"Ping the Stratis node. This verifies your Stratis node is up and the execution and consensus client HTTP APIs are reachable by strac."`
-	Info          InfoCmd      `cmd:"" help:"Get information on the Stratis network."`
-	Account       AccountCmd   `cmd:"" help:"Work with Stratis accounts."`
-	Validator     ValidatorCmd `cmd:"" help:"Get info on Stratis validators."`
-	//Wallet        WalletCmd    `cmd:"" help:"Work with wallets"`
+	Debug               bool          `help:"Enable debug mode."`
+	Output              string        `help:"The format to print command results in: text, json or yaml. Logs are always written to stderr." enum:"text,json,yaml" default:"text"`
+	Profile             string        `help:"The profile in the config file ~/.struck/config.yaml to take the endpoint, timeout and network defaults from." default:""`
+	Network             string        `help:"The Stratis network to use: mainnet or auroria. Sets the default execution and consensus client URLs and the chain id the execution client must be on." enum:"mainnet,auroria" default:"mainnet"`
+	Auroria             bool          `help:"Use the Auroria testnet. Deprecated: use --network auroria." hidden:""`
+	HttpUrl             string        `help:"The URL of the Stratis execution client HTTP or WebSocket (ws:// or wss://) API. WebSocket endpoints support subscriptions e.g. for block watch. Use a comma-separated list of HTTP URLs to fail over to the next URL on the expected chain when an endpoint is unreachable. Defaults to https://rpc.stratisevm.com on mainnet and https://auroria.rpc.stratisevm.com/ on auroria." default:""`
+	JwtSecret           string        `help:"Path to a file containing the hex-encoded 32-byte JWT secret used to authenticate with the execution client." default:""`
+	BeaconHttpUrl       string        `help:"The URL of the Stratis consensus client HTTP API. Defaults to http://localhost:3500." default:""`
+	HttpHeader          []string      `help:"An HTTP header in Key: Value form to send with each request to the execution and consensus client APIs e.g. an API key. Can be repeated." sep:"none"`
+	Timeout             int           `help:"Timeout for network operations." default:"120"`
+	NativeSymbol        string        `help:"The symbol of the chain's native token." default:"STRAX"`
+	NativeDecimals      int           `help:"The number of decimals of the chain's native token." default:"18"`
+	IgnoreChainMismatch bool          `help:"Warn instead of exiting when the execution client is on a different chain than expected." default:"false"`
+	Timezone            string        `help:"The timezone to print times in e.g. America/New_York, or local for the system timezone." default:"UTC"`
+	NoCache             bool          `help:"Fetch the genesis and spec from the consensus client instead of using the on-disk cache." default:"false"`
+	Retries             int           `help:"The maximum number of attempts for connecting to the execution client and for consensus client requests that fail with a transient error during validator scans." default:"3"`
+	Concurrency         int           `help:"The maximum number of concurrent requests strac will make to the execution and consensus clients." default:"8"`
+	HeaderCacheSize     int           `help:"The maximum number of beacon block headers cached for each epoch scanned for attestations. Lower it to reduce memory use when scanning large epoch ranges." default:"256"`
+	Ping                PingCmd       `cmd:"" help:"Ping the Stratis node. This verifies your Stratis node is up and the execution and consensus client HTTP APIs are reachable by strac."`
+	PingAll             PingAllCmd    `cmd:"" help:"Check the reachability, chain id, latest block, and sync status of a list of execution client endpoints."`
+	Info                InfoCmd       `cmd:"" help:"Get information on the Stratis network."`
+	Account             AccountCmd    `cmd:"" help:"Work with Stratis accounts."`
+	Validator           ValidatorCmd  `cmd:"" help:"Get info on Stratis validators."`
+	Block               BlockCmd      `cmd:"" help:"Get info on Stratis execution blocks."`
+	Tx                  TxCmd         `cmd:"" help:"Get info on Stratis transactions."`
+	Monitor             MonitorCmd    `cmd:"" help:"Monitor the health of the Stratis network."`
+	Node                NodeCmd       `cmd:"" help:"Work with the Stratis node."`
+	Gas                 GasCmd        `cmd:"" help:"Get the suggested gas price and priority fee and the recent fee history."`
+	Wait                WaitCmd       `cmd:"" help:"Wait until the start of a chain epoch."`
+	Repl                ReplCmd       `cmd:"" help:"Start an interactive session that runs strac commands reusing the client connections."`
+	Completion          CompletionCmd `cmd:"" help:"Output the shell completion script for bash, zsh or fish e.g. source <(strac completion bash)."`
+	Commands            CommandsCmd   `cmd:"" help:"List every strac command and the clients and beacon provider interfaces it needs."`
+	Wallet              WalletCmd     `cmd:"" help:"Work with wallets."`
 }
 
 var log = logging.Logger("strac/main")
@@ -113,48 +472,138 @@ func main() {
 		figlet4go.ColorMagenta,
 		figlet4go.ColorYellow,
 	}
-	renderStr, _ := ascii.RenderOpts("strac", options)
-	fmt.Print(renderStr)
-	ctx := kong.Parse(&CLI)
+	// The completion script is sourced by the shell and JSON and YAML output is parsed by other tools so they must be the only output.
+	output := flagArg(os.Args[1:], "--output")
+	if flagArg(os.Args[1:], "--format") == "json" || util.Contains(os.Args, "--dump-faults") {
+		output = "json"
+	}
+	if !util.Contains(os.Args, "completion") && !util.Contains(os.Args, "--json") && output != "json" && output != "yaml" {
+		renderStr, _ := ascii.RenderOpts("strac", options)
+		fmt.Print(renderStr)
+	}
+	parser, err := kong.New(&CLI, kong.Configuration(configLoader(flagArg(os.Args[1:], "--profile")), ConfigFile))
+	if err != nil {
+		log.Fatalf("error loading config file: %v", err)
+	}
+	ctx, err := parser.Parse(blockNumberArgs(os.Args[1:]))
+	parser.FatalIfErrorf(err)
+	if CLI.Profile != "" && !configLoaded {
+		log.Fatalf("profile %s was specified but the config file %s does not exist", CLI.Profile, ConfigFile)
+	}
+	if ctx.Command() == "commands" || strings.HasPrefix(ctx.Command(), "completion") {
+		// Listing the commands and shell completion don't need any clients.
+		ctx.FatalIfErrorf(ctx.Run())
+		return
+	}
+	if CLI.NativeDecimals < 0 {
+		log.Fatalf("the native token decimals must not be negative")
+	}
+	if CLI.Concurrency < 1 {
+		log.Fatalf("the concurrency must be at least 1")
+	}
+	util.Concurrency = util.NewLimiter(CLI.Concurrency)
+	if CLI.Retries < 1 {
+		log.Fatalf("the maximum number of attempts set by --retries must be at least 1")
+	}
+	util.Retries = CLI.Retries
+	if CLI.HeaderCacheSize < 1 {
+		log.Fatalf("the header cache size must be at least 1")
+	}
+	util.HeaderCacheSize = CLI.HeaderCacheSize
+	if err := util.SetTimezone(CLI.Timezone); err != nil {
+		log.Fatalf("%v", err)
+	}
+	util.NativeSymbol = CLI.NativeSymbol
+	util.Output = CLI.Output
+	util.NativeDecimals = CLI.NativeDecimals
 	_ctx, cancel := context.WithTimeout(context.Background(), time.Duration(CLI.Timeout)*time.Second)
 	blockchain.Ctx = _ctx
 	defer cancel()
-	if CLI.Auroria && CLI.HttpUrl == "https://rpc.stratisevm.com" {
-		CLI.HttpUrl = "https://auroria.rpc.stratisevm.com/"
+	if CLI.Auroria {
+		log.Warnf("--auroria is deprecated, use --network auroria")
+		CLI.Network = "auroria"
+	}
+	network := blockchain.Networks[CLI.Network]
+	blockchain.Network = CLI.Network
+	if CLI.HttpUrl == "" {
+		CLI.HttpUrl = network.HttpUrl
 	}
-	err := blockchain.InitEC(CLI.HttpUrl)
+	if CLI.BeaconHttpUrl == "" {
+		CLI.BeaconHttpUrl = network.BeaconHttpUrl
+	}
+	blockchain.NoCache = CLI.NoCache
+	headers, err := util.ParseHeaders(CLI.HttpHeader)
 	if err != nil {
-		log.Fatalf("error connecting to execution client API at %s: %v", CLI.HttpUrl, err)
+		log.Fatalf("%v", err)
 	}
-	log.Infof("Using execution client API at %v.", CLI.HttpUrl)
-
-	cid, err := blockchain.GetChainID()
+	// Public endpoints occasionally drop the first request on a cold connection so connecting and getting the chain id
+	// are retried before giving up.
+	attempt := 0
+	err = util.RetryWithBackoff(blockchain.Ctx, util.Retries, func() error {
+		attempt++
+		err := blockchain.InitEC(CLI.HttpUrl, CLI.JwtSecret, headers, network.ChainID)
+		if err != nil {
+			log.Debugf("Attempt %d of %d to connect to execution client API at %s failed: %v", attempt, util.Retries, CLI.HttpUrl, err)
+		}
+		return err
+	})
 	if err != nil {
-		log.Fatalf("could not get chain id")
+		log.Fatalf("error connecting to execution client API at %s: %v", CLI.HttpUrl, err)
 	}
+	log.Infof("Using execution client API at %v.", blockchain.CurrentHttpUrl())
 
-	if CLI.Auroria && cid.Cmp(big.NewInt(205205)) != 0 {
-		if cid == big.NewInt(105105) {
-			log.Fatalf("auroria testnet specified but execution client is on mainnet")
-		} else {
-			log.Fatalf("auroria testnet specified but execution client is on chain id %v", cid)
+	attempt = 0
+	var cid *big.Int
+	err = util.RetryWithBackoff(blockchain.Ctx, util.Retries, func() error {
+		attempt++
+		var err error
+		cid, err = blockchain.GetChainID()
+		if err != nil && blockchain.IsAuthError(err) {
+			return util.Permanent(err)
+		} else if err != nil {
+			log.Debugf("Attempt %d of %d to get chain id from execution client API at %s failed: %v", attempt, util.Retries, blockchain.CurrentHttpUrl(), err)
 		}
-	} else if !CLI.Auroria && cid.Cmp(big.NewInt(105105)) != 0 {
-		if cid == big.NewInt(205205) {
-			log.Fatalf("mainnet specified but execution client is on auroria testnet")
+		return err
+	})
+	if err != nil && blockchain.IsAuthError(err) {
+		log.Fatalf("execution client API at %s rejected the authentication: %v", blockchain.CurrentHttpUrl(), err)
+	} else if err != nil {
+		log.Fatalf("could not get chain id from execution client API at %s after %d attempts: %v", blockchain.CurrentHttpUrl(), attempt, err)
+	}
+
+	mismatch := log.Fatalf
+	if CLI.IgnoreChainMismatch {
+		mismatch = log.Warnf
+	}
+	if cid.Cmp(network.ChainID) != 0 {
+		if name := blockchain.NetworkName(cid); name != "" {
+			mismatch("%s specified but execution client is on %s", CLI.Network, name)
 		} else {
-			log.Fatalf("mainnet specified but execution client is on chain id %v", cid)
+			mismatch("%s specified but execution client is on chain id %v", CLI.Network, cid)
 		}
 	}
 
-	if util.Contains(ctx.Args, "info") || util.Contains(ctx.Args, "validator") {
-		err := blockchain.InitCC(CLI.BeaconHttpUrl, CLI.Timeout)
+	path := ctx.Selected().Path()
+	if util.Contains(optionalConsensusCommands, path) {
+		err := blockchain.InitCC(CLI.BeaconHttpUrl, CLI.Timeout, headers)
+		if err != nil {
+			log.Warnf("consensus client API at %s is not available, commands that need it will fail: %v", CLI.BeaconHttpUrl, err)
+		} else {
+			log.Infof("Using consensus client API at %v.", CLI.BeaconHttpUrl)
+		}
+	} else if needsConsensusClient(path) || CLI.Account.Balance.Watch == "slot" {
+		err := blockchain.InitCC(CLI.BeaconHttpUrl, CLI.Timeout, headers)
 		if err != nil {
-			log.Fatalf("error connecting to consensus client API at %s: %v", CLI.BeaconHttpUrl, err)
+			log.Fatalf("could not connect to the consensus client API at %s: %v. Set --beacon-http-url to the URL of a reachable consensus client HTTP API.", CLI.BeaconHttpUrl, err)
 		} else {
 			log.Infof("Using consensus client API at %v.", CLI.BeaconHttpUrl)
 		}
 	}
+	if CLI.Validator.LabelsFile != "" {
+		if err := validators.LoadLabels(CLI.Validator.LabelsFile); err != nil {
+			log.Fatalf("error loading validator labels: %v", err)
+		}
+	}
 	ctx.FatalIfErrorf(ctx.Run(&kong.Context{}))
 }
 
@@ -162,30 +611,341 @@ func (l *PingCmd) Run(ctx *kong.Context) error {
 	return blockchain.Ping()
 }
 
-func (l *InfoCmd) Run(ctx *kong.Context) error {
-	return blockchain.Info(l.Spec, l.Genesis, l.Peers)
+func (l *PingAllCmd) Run(ctx *kong.Context) error {
+	return blockchain.PingAll(l.Urls, blockchain.Networks[CLI.Network].ChainID, time.Duration(l.EndpointTimeout)*time.Second)
+}
+
+func (l *NodeWaitSyncedCmd) Run(ctx *kong.Context) error {
+	if l.Interval < 1 {
+		return fmt.Errorf("the interval must be at least 1 second")
+	}
+	interval := time.Duration(l.Interval) * time.Second
+	if blockchain.BeaconClient != nil {
+		if chainTime, err := chaintime.NewFromBeaconClient(); err == nil {
+			interval = chainTime.SlotDuration()
+		}
+	}
+	return blockchain.WaitSynced(interval)
+}
+
+func (l *WaitCmd) Run(ctx *kong.Context) error {
+	return chaintime.Wait(l.Epoch)
+}
+
+func (l *ReplCmd) Run(ctx *kong.Context) error {
+	replCLI := CLI
+	parser, err := kong.New(&replCLI, kong.Name("strac"), kong.Exit(func(int) {}), kong.Configuration(configLoader(CLI.Profile), ConfigFile))
+	if err != nil {
+		return err
+	}
+	replGlobals := func() error {
+		if replCLI.Debug {
+			logging.SetAllLoggers(logging.LevelDebug)
+		} else if os.Getenv("GOLOG_LOG_LEVEL") == "" {
+			logging.SetAllLoggers(logging.LevelInfo)
+		}
+		if replCLI.NativeDecimals < 0 {
+			return fmt.Errorf("the native token decimals must not be negative")
+		} else if replCLI.Concurrency < 1 {
+			return fmt.Errorf("the concurrency must be at least 1")
+		} else if replCLI.Retries < 1 {
+			return fmt.Errorf("the maximum number of attempts set by --retries must be at least 1")
+		} else if replCLI.HeaderCacheSize < 1 {
+			return fmt.Errorf("the header cache size must be at least 1")
+		}
+		if err := util.SetTimezone(replCLI.Timezone); err != nil {
+			return err
+		}
+		util.Concurrency = util.NewLimiter(replCLI.Concurrency)
+		util.Retries = replCLI.Retries
+		util.HeaderCacheSize = replCLI.HeaderCacheSize
+		util.NativeSymbol = replCLI.NativeSymbol
+		util.NativeDecimals = replCLI.NativeDecimals
+		util.Output = replCLI.Output
+		if replCLI.Validator.LabelsFile != "" {
+			if err := validators.LoadLabels(replCLI.Validator.LabelsFile); err != nil {
+				return util.WrapError(err, "error loading validator labels")
+			}
+		}
+		return nil
+	}
+	log.Info("Enter a command e.g. account balance 0x... or type exit or press Ctrl-D to quit.")
+	for {
+		line, err := prompt.Stdin.PromptInput("strac> ")
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			// Ctrl-C aborts the current line only.
+			continue
+		}
+		args := strings.Fields(line)
+		if len(args) == 0 {
+			continue
+		} else if args[0] == "exit" || args[0] == "quit" {
+			return nil
+		} else if args[0] == "repl" {
+			log.Errorf("already in the REPL")
+			continue
+		}
+		prompt.Stdin.AppendHistory(line)
+
+		kctx, err := parser.Parse(blockNumberArgs(args))
+		if err != nil {
+			log.Errorf("%v", err)
+			continue
+		}
+		// The global flags given on the line apply to that command only. The client connections are reused so flags
+		// that select the network or endpoints have no effect.
+		if err := replGlobals(); err != nil {
+			log.Errorf("%v", err)
+			continue
+		}
+		// Each command gets its own timeout as the session may last much longer than the timeout.
+		cmdCtx, cancel := context.WithTimeout(context.Background(), time.Duration(replCLI.Timeout)*time.Second)
+		blockchain.Ctx = cmdCtx
+		if err := kctx.Run(&kong.Context{}); err != nil {
+			log.Errorf("%v", err)
+		}
+		cancel()
+	}
+}
+
+func (l *MonitorFinalityCmd) Run(ctx *kong.Context) error {
+	return validators.MonitorFinality(l.Threshold)
+}
+
+func (l *InfoTimeCmd) Run(ctx *kong.Context) error {
+	return chaintime.Time(l.Epoch, l.Slot)
+}
+
+func (l *InfoWhenCmd) Run(ctx *kong.Context) error {
+	return chaintime.When(l.Date, l.Epoch)
+}
+
+func (l *InfoChainCmd) Run(ctx *kong.Context) error {
+	if l.Json {
+		util.Output = "json"
+	}
+	chainFlags := l.Spec || l.Genesis || l.GenesisForkOnly || l.Peers || l.PeersSummary || l.Capabilities || l.Inactivity || l.HeadConsistency
+	if l.ValidatorPubkey == "" {
+		return blockchain.Info(l.Spec, l.Genesis, l.GenesisForkOnly, l.Peers, l.PeersDirection, l.PeersState, l.PeersSummary, l.Capabilities, l.Inactivity, l.HeadConsistency)
+	} else if !chainFlags {
+		return validators.Info([]string{l.ValidatorPubkey}, "")
+	}
+	// JSON and YAML output must be a single document so the chain and validator info can't both be printed.
+	if util.Output != "text" {
+		return fmt.Errorf("--validator-pubkey can't be used with other chain info flags for %s output, use validator info to get the validator's info", util.Output)
+	}
+	if err := blockchain.Info(l.Spec, l.Genesis, l.GenesisForkOnly, l.Peers, l.PeersDirection, l.PeersState, l.PeersSummary, l.Capabilities, l.Inactivity, l.HeadConsistency); err != nil {
+		return err
+	}
+	return validators.Info([]string{l.ValidatorPubkey}, "")
+}
+
+func (l *BlockAvgTimeCmd) Run(ctx *kong.Context) error {
+	return blockchain.AvgBlockTime(l.Window)
+}
+
+func (l *TxReceiptCmd) Run(ctx *kong.Context) error {
+	return blockchain.Receipt(l.Hash, l.Abi)
+}
+
+func (l *TxEstimateCmd) Run(ctx *kong.Context) error {
+	return blockchain.Estimate(l.From, l.To, l.Amount, l.Data)
+}
+
+func (l *GasCmd) Run(ctx *kong.Context) error {
+	return blockchain.GasInfo(l.Blocks, l.Percentiles)
+}
+
+func (l *BlockInfoCmd) Run(ctx *kong.Context) error {
+	if l.Number == "latest" || l.Number == "-1" {
+		return blockchain.BlockInfo(-1)
+	}
+	number, err := strconv.ParseInt(l.Number, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid block number %s", l.Number)
+	} else if number < 0 {
+		return fmt.Errorf("invalid block number %s: use -1 or latest for the latest block", l.Number)
+	}
+	return blockchain.BlockInfo(number)
+}
+
+func (l *BlockWatchCmd) Run(ctx *kong.Context) error {
+	return blockchain.WatchBlocks(l.Follow, l.Subscribe)
+}
+
+func (l *AccountBalanceDeltaCmd) Run(ctx *kong.Context) error {
+	return accounts.BalanceDelta(l.Account, l.FromBlock, l.ToBlock)
+}
+
+func (l *AccountImportCmd) Run(ctx *kong.Context) error {
+	return accounts.ImportKey(l.PrivateKey, l.WalletDir)
+}
+
+func (l *AccountSignCmd) Run(ctx *kong.Context) error {
+	return accounts.SignMessage(l.Message, l.From, l.WalletDir, l.KeyStdin)
+}
+
+func (l *AccountVerifyCmd) Run(ctx *kong.Context) error {
+	return accounts.VerifyMessage(l.Message, l.Signature, l.Address)
+}
+
+func (l *AccountSendCmd) Run(ctx *kong.Context) error {
+	return accounts.SendTransaction(l.From, l.To, l.Amount, l.WalletDir, l.KeyStdin)
+}
+
+func (l *AccountEstimateBatchCmd) Run(ctx *kong.Context) error {
+	return accounts.EstimateBatch(l.ToFile, l.From)
 }
 
 func (l *NewAccountCmd) Run(ctx *kong.Context) error {
+	if l.WalletDir != "" {
+		return accounts.NewAccount(&l.WalletDir)
+	}
 	return accounts.NewAccount(nil)
 }
 
+func (l *AccountAuditCmd) Run(ctx *kong.Context) error {
+	return accounts.Audit(l.WalletDir)
+}
+
+func (l *AccountRekeyCmd) Run(ctx *kong.Context) error {
+	return accounts.Rekey(l.File)
+}
+
 func (l *AccountBalanceCmd) Run(ctx *kong.Context) error {
-	return accounts.BalanceAt(l.Account, l.Block)
+	if l.Date != "" && l.Block != 0 {
+		return fmt.Errorf("can't specify both block and date")
+	} else if l.Watch != "" && (l.Date != "" || l.Block != 0) {
+		return fmt.Errorf("can't watch the balance at a past block or date")
+	} else if l.Date != "" {
+		return accounts.BalanceAtDate(l.Account, l.Date, l.Humanize)
+	} else if l.Watch != "" {
+		return accounts.WatchBalance(l.Account, l.Watch, l.Humanize)
+	}
+	return accounts.BalanceAt(l.Account, l.Block, l.Humanize)
+}
+
+func (l *AccountCodeCmd) Run(ctx *kong.Context) error {
+	return blockchain.CodeAt(l.Account, l.Block, l.Dump)
+}
+
+func (l *AccountStorageCmd) Run(ctx *kong.Context) error {
+	return blockchain.StorageAt(l.Account, l.Slot, l.Block)
+}
+
+func (l *AccountNonceCmd) Run(ctx *kong.Context) error {
+	return blockchain.NonceAt(l.Account, l.Block)
+}
+
+func (l *AccountBalancesCmd) Run(ctx *kong.Context) error {
+	return accounts.Balances(l.File, l.Account, l.Block, l.Humanize)
+}
+
+func (l *AccountTokenBalanceCmd) Run(ctx *kong.Context) error {
+	return accounts.TokenBalanceAt(l.Account, l.Token, l.Block, l.Humanize)
 }
 
 func (l *ValidatorInfoCmd) Run(ctx *kong.Context) error {
-	return validators.Info(l.PubKey)
+	if l.Json {
+		util.Output = "json"
+	}
+	return validators.Info(l.PubKey, l.GroupBy)
 }
 
 func (l *ValidatorPerfCmd) Run(ctx *kong.Context) error {
-	return validators.Perf(l.Validators, l.StateID, l.Start, l.End, l.NumEpochs)
+	// The attestation data of each fault is part of the JSON summaries.
+	if l.Format == "json" || l.DumpFaults {
+		util.Output = "json"
+	}
+	return validators.Perf(l.Validators, l.StateID, l.Start, l.End, l.NumEpochs, l.MissedBlocks, l.FirstSlotOnly, l.AllowUnfinalized, l.SummaryOnly, l.Markdown, l.IncludeDuties, l.Csv)
+}
+
+func (l *ValidatorProposalsCmd) Run(ctx *kong.Context) error {
+	if l.Start != "" || l.End != "" || l.NumEpochs != "" {
+		return validators.ProposalHistory(l.Validators, l.Start, l.End, l.NumEpochs)
+	}
+	return validators.Proposals(l.Validators, l.Epoch)
+}
+
+func (l *ValidatorCheckCredentialsCmd) Run(ctx *kong.Context) error {
+	return validators.CheckCredentials(l.Validators)
+}
+
+func (l *ValidatorDumpSetCmd) Run(ctx *kong.Context) error {
+	return validators.DumpSet(l.State, l.Out)
+}
+
+func (l *ValidatorSyncRewardsCmd) Run(ctx *kong.Context) error {
+	return validators.SyncCommitteeRewards(l.Validators, l.Start, l.End, l.NumEpochs)
+}
+
+func (l *ValidatorBalanceHistoryCmd) Run(ctx *kong.Context) error {
+	return validators.BalanceHistory(l.Validators, l.Start, l.End, l.NumEpochs)
+}
+
+func (l *ValidatorMissedRewardsCmd) Run(ctx *kong.Context) error {
+	return validators.MissedRewards(l.Validators, "head", l.Start, l.End, l.NumEpochs)
+}
+
+func (l *ValidatorListCmd) Run(ctx *kong.Context) error {
+	return validators.List(l.Status, l.State, l.Limit)
+}
+
+func (l *ValidatorSlashingsCmd) Run(ctx *kong.Context) error {
+	return validators.Slashings(l.Validators, l.Start, l.End, l.NumEpochs)
+}
+
+func (l *ValidatorProposerEfficiencyCmd) Run(ctx *kong.Context) error {
+	return validators.ProposerEfficiency(l.Validators, l.Start, l.End, l.NumEpochs)
+}
+
+func (l *ValidatorMonitorCmd) Run(ctx *kong.Context) error {
+	return validators.Monitor(l.Validators)
+}
+
+func (l *ValidatorIncomeCmd) Run(ctx *kong.Context) error {
+	return validators.Income(l.Validators, l.NumEpochs, l.Fiat, l.PriceTokenID, l.Price)
+}
+
+func (l *ValidatorWithdrawalsCmd) Run(ctx *kong.Context) error {
+	return validators.Withdrawals(l.Validators, l.Start, l.End, l.NumEpochs, l.Slots)
+}
+
+func (l *ValidatorVerifyControlCmd) Run(ctx *kong.Context) error {
+	return validators.VerifyControl(l.Address, l.Validator, l.FromBlock, l.ToBlock)
+}
+
+func (l *ValidatorProposalScorecardCmd) Run(ctx *kong.Context) error {
+	return validators.ProposalScorecard(l.Validators, l.Start, l.End, l.NumEpochs)
+}
+
+func (l *ValidatorTimeseriesCmd) Run(ctx *kong.Context) error {
+	return validators.Timeseries(l.Validators, l.StateID, l.Start, l.End, l.NumEpochs, l.Out)
+}
+
+func (l *ValidatorDepositRootCmd) Run(ctx *kong.Context) error {
+	return validators.DepositDataRoot(l.PubKey, l.WithdrawalCredentials, l.Amount, l.Signature)
+}
+
+func (l *ValidatorWatchCmd) Run(ctx *kong.Context) error {
+	return validators.Watch(l.Validator)
 }
 
 func (l *CreateWalletCmd) Run(ctx *kong.Context) error {
-	log.Info(l.Type)
-	log.Info(l.Name)
-	return nil
+	switch l.Type {
+	case "hd":
+		return wallets.CreateHD(l.Name, l.WalletDir, l.Count, l.MnemonicStdin)
+	case "nd":
+		return fmt.Errorf("nd wallets are directories of individual keystore files: use account new or account import with --wallet-dir to add accounts to one")
+	default:
+		return fmt.Errorf("unknown wallet type %s: use hd", l.Type)
+	}
+}
+
+func (l *ListWalletCmd) Run(ctx *kong.Context) error {
+	return wallets.List(l.Type, l.Name, l.WalletDir)
 }
 
 func (l *AccountAddressCmd) Run(ctx *kong.Context) error {
@@ -14,10 +14,12 @@
 package util
 
 import (
+	"container/list"
 	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"sync"
 
 	eth2client "github.com/attestantio/go-eth2-client"
 	"github.com/attestantio/go-eth2-client/api"
@@ -25,21 +27,38 @@ import (
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 )
 
-// BeaconBlockHeaderCache is a cache of beacon block headers.
+// HeaderCacheSize is the maximum number of headers kept by each beacon block header cache.
+var HeaderCacheSize = 256
+
+// BeaconBlockHeaderCache is a cache of beacon block headers that evicts the least recently used header when it holds
+// its maximum number of headers. It is safe for concurrent use.
 type BeaconBlockHeaderCache struct {
 	beaconBlockHeadersProvider eth2client.BeaconBlockHeadersProvider
-	entries                    map[phase0.Slot]*beaconBlockHeaderEntry
+	size                       int
+	mutex                      sync.Mutex
+	entries                    map[phase0.Slot]*list.Element
+	// order holds the entries from most to least recently used.
+	order     *list.List
+	hits      int
+	misses    int
+	evictions int
 }
 
-// NewBeaconBlockHeaderCache makes a new beacon block header cache.
-func NewBeaconBlockHeaderCache(provider eth2client.BeaconBlockHeadersProvider) *BeaconBlockHeaderCache {
+// NewBeaconBlockHeaderCache makes a new beacon block header cache holding at most size headers.
+func NewBeaconBlockHeaderCache(provider eth2client.BeaconBlockHeadersProvider, size int) *BeaconBlockHeaderCache {
+	if size < 1 {
+		size = 1
+	}
 	return &BeaconBlockHeaderCache{
 		beaconBlockHeadersProvider: provider,
-		entries:                    make(map[phase0.Slot]*beaconBlockHeaderEntry),
+		size:                       size,
+		entries:                    make(map[phase0.Slot]*list.Element),
+		order:                      list.New(),
 	}
 }
 
 type beaconBlockHeaderEntry struct {
+	slot    phase0.Slot
 	present bool
 	value   *apiv1.BeaconBlockHeader
 }
@@ -51,26 +70,47 @@ func (b *BeaconBlockHeaderCache) Fetch(ctx context.Context,
 	*apiv1.BeaconBlockHeader,
 	error,
 ) {
-	entry, exists := b.entries[slot]
-	if !exists {
-		response, err := b.beaconBlockHeadersProvider.BeaconBlockHeader(ctx, &api.BeaconBlockHeaderOpts{Block: fmt.Sprintf("%d", slot)})
-		if err != nil {
-			var apiErr *api.Error
-			if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
-				entry = &beaconBlockHeaderEntry{
-					present: false,
-				}
-			} else {
-				return nil, err
-			}
-		} else {
-			entry = &beaconBlockHeaderEntry{
-				present: true,
-				value:   response.Data,
-			}
+	b.mutex.Lock()
+	if element, exists := b.entries[slot]; exists {
+		b.hits++
+		b.order.MoveToFront(element)
+		b.mutex.Unlock()
+		return element.Value.(*beaconBlockHeaderEntry).value, nil
+	}
+	b.misses++
+	b.mutex.Unlock()
+
+	// The header is fetched without holding the lock so fetches of other slots aren't blocked.
+	entry := &beaconBlockHeaderEntry{slot: slot}
+	response, err := b.beaconBlockHeadersProvider.BeaconBlockHeader(ctx, &api.BeaconBlockHeaderOpts{Block: fmt.Sprintf("%d", slot)})
+	if err != nil {
+		var apiErr *api.Error
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			return nil, err
 		}
+	} else {
+		entry.present = true
+		entry.value = response.Data
+	}
 
-		b.entries[slot] = entry
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if _, exists := b.entries[slot]; !exists {
+		b.entries[slot] = b.order.PushFront(entry)
+		for b.order.Len() > b.size {
+			oldest := b.order.Back()
+			b.order.Remove(oldest)
+			delete(b.entries, oldest.Value.(*beaconBlockHeaderEntry).slot)
+			b.evictions++
+		}
 	}
 	return entry.value, nil
 }
+
+// Stats returns the number of fetches served from the cache, the number that fetched the header from the beacon node,
+// and the number of headers evicted.
+func (b *BeaconBlockHeaderCache) Stats() (hits int, misses int, evictions int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.hits, b.misses, b.evictions
+}
@@ -0,0 +1,125 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// headerProvider serves headers for every slot except the missing ones and counts the fetches of each slot.
+type headerProvider struct {
+	missing map[phase0.Slot]bool
+	failing map[phase0.Slot]bool
+	fetches map[phase0.Slot]int
+}
+
+func newHeaderProvider() *headerProvider {
+	return &headerProvider{missing: make(map[phase0.Slot]bool), failing: make(map[phase0.Slot]bool), fetches: make(map[phase0.Slot]int)}
+}
+
+func (p *headerProvider) BeaconBlockHeader(ctx context.Context, opts *api.BeaconBlockHeaderOpts) (*api.Response[*apiv1.BeaconBlockHeader], error) {
+	var slot phase0.Slot
+	if _, err := fmt.Sscanf(opts.Block, "%d", &slot); err != nil {
+		return nil, err
+	}
+	p.fetches[slot]++
+	if p.failing[slot] {
+		return nil, &api.Error{StatusCode: http.StatusInternalServerError}
+	}
+	if p.missing[slot] {
+		return nil, &api.Error{StatusCode: http.StatusNotFound}
+	}
+	return &api.Response[*apiv1.BeaconBlockHeader]{Data: &apiv1.BeaconBlockHeader{Header: &phase0.SignedBeaconBlockHeader{Message: &phase0.BeaconBlockHeader{Slot: slot}}}}, nil
+}
+
+func fetchSlot(t *testing.T, cache *BeaconBlockHeaderCache, slot phase0.Slot) {
+	t.Helper()
+	header, err := cache.Fetch(context.Background(), slot)
+	if err != nil {
+		t.Fatalf("Fetch(%d) returned error: %v", slot, err)
+	}
+	if header == nil || header.Header.Message.Slot != slot {
+		t.Fatalf("Fetch(%d) = %v, want the header of slot %d", slot, header, slot)
+	}
+}
+
+func checkStats(t *testing.T, cache *BeaconBlockHeaderCache, hits int, misses int, evictions int) {
+	t.Helper()
+	if h, m, e := cache.Stats(); h != hits || m != misses || e != evictions {
+		t.Errorf("Stats() = %d hits, %d misses, %d evictions, want %d, %d, %d", h, m, e, hits, misses, evictions)
+	}
+}
+
+func TestBeaconBlockHeaderCacheEviction(t *testing.T) {
+	provider := newHeaderProvider()
+	cache := NewBeaconBlockHeaderCache(provider, 2)
+
+	fetchSlot(t, cache, 1)
+	fetchSlot(t, cache, 2)
+	fetchSlot(t, cache, 1)
+	checkStats(t, cache, 1, 2, 0)
+
+	// Slot 2 is now the least recently used so it is evicted to make room for slot 3.
+	fetchSlot(t, cache, 3)
+	checkStats(t, cache, 1, 3, 1)
+	fetchSlot(t, cache, 1)
+	fetchSlot(t, cache, 3)
+	checkStats(t, cache, 3, 3, 1)
+	fetchSlot(t, cache, 2)
+	checkStats(t, cache, 3, 4, 2)
+
+	if provider.fetches[1] != 1 || provider.fetches[2] != 2 || provider.fetches[3] != 1 {
+		t.Errorf("provider fetches = %v, want slot 1 and 3 once and slot 2 twice", provider.fetches)
+	}
+}
+
+func TestBeaconBlockHeaderCacheMinimumSize(t *testing.T) {
+	provider := newHeaderProvider()
+	cache := NewBeaconBlockHeaderCache(provider, 0)
+	fetchSlot(t, cache, 1)
+	fetchSlot(t, cache, 1)
+	fetchSlot(t, cache, 2)
+	checkStats(t, cache, 1, 2, 1)
+}
+
+func TestBeaconBlockHeaderCacheMiss(t *testing.T) {
+	provider := newHeaderProvider()
+	provider.missing[5] = true
+	provider.failing[6] = true
+	cache := NewBeaconBlockHeaderCache(provider, 4)
+
+	// A slot without a block is cached as having no header.
+	for i := 0; i < 2; i++ {
+		header, err := cache.Fetch(context.Background(), 5)
+		if err != nil || header != nil {
+			t.Fatalf("Fetch(5) = %v, %v, want no header and no error", header, err)
+		}
+	}
+	if provider.fetches[5] != 1 {
+		t.Errorf("slot 5 fetched %d times, want 1", provider.fetches[5])
+	}
+
+	// Other errors are returned and not cached, so the next fetch falls back to the provider again.
+	for i := 0; i < 2; i++ {
+		var apiErr *api.Error
+		if _, err := cache.Fetch(context.Background(), 6); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+			t.Fatalf("Fetch(6) returned error %v, want the provider's error", err)
+		}
+	}
+	if provider.fetches[6] != 2 {
+		t.Errorf("slot 6 fetched %d times, want 2", provider.fetches[6])
+	}
+	provider.failing[6] = false
+	fetchSlot(t, cache, 6)
+	fetchSlot(t, cache, 6)
+	if provider.fetches[6] != 3 {
+		t.Errorf("slot 6 fetched %d times, want 3", provider.fetches[6])
+	}
+	checkStats(t, cache, 2, 4, 0)
+}
@@ -0,0 +1,37 @@
+package util
+
+// Limiter bounds the number of operations that may run concurrently.
+type Limiter struct {
+	slots chan struct{}
+}
+
+// Concurrency is the limiter shared by every concurrent operation so strac as a whole respects a single bound.
+var Concurrency = NewLimiter(8)
+
+// NewLimiter creates a limiter allowing at most n concurrent operations. Values of n less than 1 are treated as 1.
+func NewLimiter(n int) *Limiter {
+	if n < 1 {
+		n = 1
+	}
+	return &Limiter{slots: make(chan struct{}, n)}
+}
+
+// Acquire blocks until a slot is available.
+func (l *Limiter) Acquire() {
+	l.slots <- struct{}{}
+}
+
+// TryAcquire obtains a slot if one is available without blocking and reports whether it did.
+func (l *Limiter) TryAcquire() bool {
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Release frees a slot previously obtained with Acquire.
+func (l *Limiter) Release() {
+	<-l.slots
+}
@@ -0,0 +1,41 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Output is the format command results are printed in: text, json or yaml.
+var Output = "text"
+
+// Render prints the result of a command in the output format. For text output the text function prints the result for
+// people, for json and yaml output the result is serialized to stdout.
+func Render(v any, text func()) error {
+	switch Output {
+	case "json":
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return WrapError(err, "could not serialize output to JSON")
+		}
+		fmt.Println(string(data))
+	case "yaml":
+		// Converting through JSON uses the json field names of the result so both formats have the same keys.
+		data, err := json.Marshal(v)
+		if err != nil {
+			return WrapError(err, "could not serialize output to YAML")
+		}
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return WrapError(err, "could not serialize output to YAML")
+		}
+		if data, err = yaml.Marshal(doc); err != nil {
+			return WrapError(err, "could not serialize output to YAML")
+		}
+		fmt.Print(string(data))
+	default:
+		text()
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// PriceApiUrl is the URL of the CoinGecko compatible API used to look up token prices.
+var PriceApiUrl = "https://api.coingecko.com/api/v3/simple/price"
+
+// FetchPrice returns the current price of the token with the given CoinGecko id e.g. stratis in the given fiat currency e.g. usd.
+func FetchPrice(tokenID string, fiat string) (float64, error) {
+	tokenID, fiat = strings.ToLower(tokenID), strings.ToLower(fiat)
+	u := fmt.Sprintf("%s?ids=%s&vs_currencies=%s", PriceApiUrl, url.QueryEscape(tokenID), url.QueryEscape(fiat))
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(u)
+	if err != nil {
+		return 0, WrapError(err, "could not get price of %s in %s", tokenID, fiat)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("could not get price of %s in %s: %s", tokenID, fiat, resp.Status)
+	}
+	prices := make(map[string]map[string]float64)
+	if err := json.NewDecoder(resp.Body).Decode(&prices); err != nil {
+		return 0, WrapError(err, "could not decode price response")
+	}
+	price, ok := prices[tokenID][fiat]
+	if !ok {
+		return 0, fmt.Errorf("no price of %s in %s was returned", tokenID, fiat)
+	}
+	return price, nil
+}
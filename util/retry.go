@@ -0,0 +1,67 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// Retries is the maximum number of attempts made for requests that are retried with RetryWithBackoff.
+var Retries = 3
+
+// retryBaseDelay is the delay before the first retry, doubled after each attempt.
+var retryBaseDelay = 500 * time.Millisecond
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks an error returned to RetryWithBackoff as one that should not be retried.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// RetryWithBackoff calls fn up to attempts times until it succeeds, waiting an exponentially increasing delay with jitter
+// between attempts. It stops early if fn returns an error marked with Permanent, which is returned unwrapped, or if
+// the context is done or its deadline would pass before the next attempt.
+func RetryWithBackoff(ctx context.Context, attempts int, fn func() error) error {
+	delay := retryBaseDelay
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+		if attempt >= attempts {
+			return err
+		}
+		// Wait a random time between half and all of the delay so concurrent callers don't retry in lockstep.
+		wait := delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return err
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
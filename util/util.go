@@ -1,12 +1,19 @@
 package util
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
+	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/console/prompt"
 	"github.com/ethereum/go-ethereum/params"
@@ -16,6 +23,50 @@ var AppData = filepath.Join(GetUserHomeDir(), ".struck")
 
 var Shutdown = false
 
+// Location is the timezone times are rendered in.
+var Location = time.UTC
+
+// NativeSymbol and NativeDecimals describe the chain's native token.
+var NativeSymbol = "STRAX"
+var NativeDecimals = 18
+
+// SetTimezone sets the timezone times are rendered in from an IANA zone name e.g. America/New_York, or local for the system timezone.
+func SetTimezone(name string) error {
+	if strings.EqualFold(name, "local") {
+		Location = time.Local
+		return nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return fmt.Errorf("unknown timezone %s: %v", name, err)
+	}
+	Location = loc
+	return nil
+}
+
+// ToChecksumAddress validates a hex address and returns it in its EIP-55 mixed-case checksum form. Addresses supplied
+// in mixed case must have a valid checksum, which catches most copy-paste errors.
+func ToChecksumAddress(address string) (string, error) {
+	if !common.IsHexAddress(address) {
+		return "", fmt.Errorf("invalid address %s: must be a 40 character hex string beginning with 0x", address)
+	}
+	checksummed := common.HexToAddress(address).Hex()
+	digits := strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X")
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) && digits != checksummed[2:] {
+		return "", fmt.Errorf("address %s has an invalid EIP-55 checksum, check that it was copied correctly", address)
+	}
+	return checksummed, nil
+}
+
+// ParseAddress parses a hex address, rejecting mixed-case addresses with an invalid EIP-55 checksum.
+func ParseAddress(address string) (common.Address, error) {
+	checksummed, err := ToChecksumAddress(address)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return common.HexToAddress(checksummed), nil
+}
+
 func GetUserHomeDir() string {
 	h, err := os.UserHomeDir()
 	if err != nil {
@@ -51,6 +102,31 @@ func ToPubKey(key string) (phase0.BLSPubKey, error) {
 		return k, nil
 	}
 }
+
+// GraffitiString converts block graffiti to a printable string, dropping the zero padding and any non-printable characters.
+func GraffitiString(graffiti [32]byte) string {
+	return strings.Map(func(r rune) rune {
+		if !unicode.IsPrint(r) {
+			return -1
+		}
+		return r
+	}, string(bytes.TrimRight(graffiti[:], "\x00")))
+}
+
+// ParseHeaders parses a list of HTTP headers in Key: Value form.
+func ParseHeaders(headers []string) (map[string]string, error) {
+	parsed := make(map[string]string)
+	for _, h := range headers {
+		key, value, found := strings.Cut(h, ":")
+		key = strings.TrimSpace(key)
+		if !found || key == "" || strings.ContainsAny(key, " \t\r\n") {
+			return nil, fmt.Errorf("invalid HTTP header %q: headers must be in Key: Value form", h)
+		}
+		parsed[http.CanonicalHeaderKey(key)] = strings.TrimSpace(value)
+	}
+	return parsed, nil
+}
+
 func WrapError(err error, msg string, params ...any) error {
 	emsg := fmt.Sprintf(msg, params...)
 	return fmt.Errorf("%s:%v", emsg, err)
@@ -59,8 +135,100 @@ func EtherToWei(val *big.Int) *big.Int {
 	return new(big.Int).Mul(val, big.NewInt(params.Ether))
 }
 
+// WeiToEther converts Wei to whole Ether, truncating towards zero so negative values convert symmetrically. A nil value converts to zero.
 func WeiToEther(val *big.Int) *big.Int {
-	return new(big.Int).Div(val, big.NewInt(params.Ether))
+	if val == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Quo(val, big.NewInt(params.Ether))
+}
+
+var decimalAmount = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// ParseAmount parses an amount with an optional unit suffix e.g. 1.5strax, 100gwei or 1000000wei into Wei.
+// A bare number is treated as an amount of the native token. Amounts more precise than 1 Wei are rejected.
+func ParseAmount(s string) (*big.Int, error) {
+	amount := strings.ToLower(strings.TrimSpace(s))
+	decimals := NativeDecimals
+	for _, unit := range []struct {
+		suffix   string
+		decimals int
+	}{
+		{"gwei", 9},
+		{"wei", 0},
+		{"ether", 18},
+		{strings.ToLower(NativeSymbol), NativeDecimals},
+	} {
+		if strings.HasSuffix(amount, unit.suffix) {
+			amount, decimals = strings.TrimSpace(strings.TrimSuffix(amount, unit.suffix)), unit.decimals
+			break
+		}
+	}
+	if strings.HasPrefix(amount, "-") {
+		return nil, fmt.Errorf("amount %s must not be negative", s)
+	}
+	// big.Rat also accepts fractions, exponents, base prefixes like 0x and digit separators, none of which are amounts.
+	if !decimalAmount.MatchString(amount) {
+		return nil, fmt.Errorf("invalid amount %s", s)
+	}
+	val, _ := new(big.Rat).SetString(amount)
+	val.Mul(val, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+	if !val.IsInt() {
+		return nil, fmt.Errorf("amount %s is more precise than 1 wei", s)
+	}
+	return val.Num(), nil
+}
+
+// formatDecimal formats a value in the smallest unit of a token with the given number of decimals as a decimal token amount
+// rounded to the given number of decimal places with trailing zeros trimmed.
+func formatDecimal(val *big.Int, decimals int, places int) string {
+	if val == nil {
+		return "0"
+	}
+	// A rational is exact so rounding to the decimal places never suffers from binary floating point error.
+	s := new(big.Rat).SetFrac(val, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)).FloatString(places)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		s = "0"
+	}
+	return s
+}
+
+// FormatUnits formats a value in the smallest unit of a token with the given number of decimals as a decimal token amount
+// without trailing zeros, optionally grouping the integer portion with thousands separators.
+func FormatUnits(val *big.Int, decimals int, humanize bool) string {
+	s := formatDecimal(val, decimals, decimals)
+	if humanize {
+		return GroupThousands(s)
+	}
+	return s
+}
+
+// FormatNative formats a value in the smallest unit of the chain's native token together with the native token symbol.
+func FormatNative(val *big.Int, humanize bool) string {
+	return FormatUnits(val, NativeDecimals, humanize) + " " + NativeSymbol
+}
+
+// GroupThousands inserts comma separators between each group of 3 digits in the integer portion of a decimal number string.
+func GroupThousands(s string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, s = s[:1], s[1:]
+	}
+	integer, fraction := s, ""
+	if i := strings.Index(s, "."); i >= 0 {
+		integer, fraction = s[:i], s[i:]
+	}
+	builder := strings.Builder{}
+	for i, c := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			builder.WriteRune(',')
+		}
+		builder.WriteRune(c)
+	}
+	return sign + builder.String() + fraction
 }
 
 func GetPassPhrase(confirmation bool) (*string, error) {
@@ -0,0 +1,116 @@
+package util
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		err  bool
+	}{
+		{in: "1", want: "1000000000000000000"},
+		{in: "1.5", want: "1500000000000000000"},
+		{in: " 2.25 ", want: "2250000000000000000"},
+		{in: "0", want: "0"},
+		{in: "1.5strax", want: "1500000000000000000"},
+		{in: "1.5STRAX", want: "1500000000000000000"},
+		{in: "1.5 strax", want: "1500000000000000000"},
+		{in: "2ether", want: "2000000000000000000"},
+		{in: "100gwei", want: "100000000000"},
+		{in: "1.5gwei", want: "1500000000"},
+		{in: "1000000wei", want: "1000000"},
+		{in: "0.000000000000000001", want: "1"},
+		{in: "0.000000001gwei", want: "1"},
+		// Amounts more precise than 1 Wei are rejected rather than rounded.
+		{in: "0.0000000000000000001", err: true},
+		{in: "0.0000000001gwei", err: true},
+		{in: "1.5wei", err: true},
+		{in: "", err: true},
+		{in: "strax", err: true},
+		{in: "abc", err: true},
+		{in: "1.5eth", err: true},
+		{in: "1e18", err: true},
+		{in: "1/2", err: true},
+		{in: "-1", err: true},
+		{in: "-1gwei", err: true},
+		{in: ".5", err: true},
+		{in: "1.", err: true},
+		{in: "0x10", err: true},
+		{in: "0X10", err: true},
+		{in: "0b11", err: true},
+		{in: "0o7", err: true},
+		{in: "1_000", err: true},
+		{in: "0x10gwei", err: true},
+		{in: "+1", err: true},
+		{in: "1 000", err: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.in)
+		if tt.err {
+			if err == nil {
+				t.Errorf("ParseAmount(%q) = %v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseAmount(%q) returned error: %v", tt.in, err)
+		} else if got.String() != tt.want {
+			t.Errorf("ParseAmount(%q) = %v, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWeiToEther(t *testing.T) {
+	huge := new(big.Int).Exp(big.NewInt(10), big.NewInt(60), nil)
+	tests := []struct {
+		in   *big.Int
+		want string
+	}{
+		{in: nil, want: "0"},
+		{in: big.NewInt(0), want: "0"},
+		{in: big.NewInt(999999999999999999), want: "0"},
+		{in: big.NewInt(1000000000000000000), want: "1"},
+		{in: big.NewInt(1500000000000000000), want: "1"},
+		// Negative values are truncated toward zero.
+		{in: big.NewInt(-1500000000000000000), want: "-1"},
+		{in: big.NewInt(-1), want: "0"},
+		{in: huge, want: new(big.Int).Exp(big.NewInt(10), big.NewInt(42), nil).String()},
+	}
+	for _, tt := range tests {
+		if got := WeiToEther(tt.in); got.String() != tt.want {
+			t.Errorf("WeiToEther(%v) = %v, want %s", tt.in, got, tt.want)
+		}
+	}
+	if huge.String() != new(big.Int).Exp(big.NewInt(10), big.NewInt(60), nil).String() {
+		t.Errorf("WeiToEther modified its argument")
+	}
+}
+
+func TestFormatDecimal(t *testing.T) {
+	large, _ := new(big.Int).SetString("123456789012345678901234567890123456789", 10)
+	tests := []struct {
+		val      *big.Int
+		decimals int
+		places   int
+		want     string
+	}{
+		{val: nil, decimals: 18, places: 18, want: "0"},
+		{val: big.NewInt(0), decimals: 18, places: 18, want: "0"},
+		{val: big.NewInt(1), decimals: 18, places: 18, want: "0.000000000000000001"},
+		{val: big.NewInt(1500000000000000000), decimals: 18, places: 18, want: "1.5"},
+		{val: big.NewInt(-1500000000000000000), decimals: 18, places: 18, want: "-1.5"},
+		{val: large, decimals: 18, places: 18, want: "123456789012345678901.234567890123456789"},
+		{val: big.NewInt(1250), decimals: 3, places: 1, want: "1.3"},
+		{val: big.NewInt(1249), decimals: 3, places: 1, want: "1.2"},
+		{val: big.NewInt(-1), decimals: 18, places: 2, want: "0"},
+		{val: big.NewInt(123), decimals: 0, places: 0, want: "123"},
+	}
+	for _, tt := range tests {
+		if got := formatDecimal(tt.val, tt.decimals, tt.places); got != tt.want {
+			t.Errorf("formatDecimal(%v, %d, %d) = %s, want %s", tt.val, tt.decimals, tt.places, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package validators
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+
+	api "github.com/attestantio/go-eth2-client/api"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/allisterb/strac/blockchain"
+	"github.com/allisterb/strac/util"
+)
+
+// depositEventTopic is the topic of the deposit contract's DepositEvent(bytes,bytes,bytes,bytes,bytes) log.
+var depositEventTopic = crypto.Keccak256Hash([]byte("DepositEvent(bytes,bytes,bytes,bytes,bytes)"))
+
+// depositLogsChunk is the number of blocks queried for deposit logs in a single request to stay within provider limits.
+const depositLogsChunk = 10000
+
+type deposit struct {
+	Block       uint64
+	TxHash      common.Hash
+	PubKey      []byte
+	Sender      common.Address
+	Amount      uint64
+	Credentials []byte
+}
+
+// VerifyControl checks whether the execution address made a deposit for the validator by scanning the deposit contract
+// logs in the block range, and whether it is the validator's withdrawal address.
+func VerifyControl(address string, validatorStr string, fromBlock uint64, toBlock uint64) error {
+	account, err := util.ParseAddress(address)
+	if err != nil {
+		return err
+	}
+	if err := Init(); err != nil {
+		return err
+	}
+	validator, err := parseValidator(blockchain.Ctx, validatorsProvider, validatorStr, "head")
+	if err != nil {
+		return err
+	}
+	name := validatorName(validator.Index, hexutil.Encode(validator.Validator.PublicKey[:]))
+
+	credentials := validator.Validator.WithdrawalCredentials
+	isWithdrawalAddress := len(credentials) == 32 && credentials[0] == 0x01 && common.BytesToAddress(credentials[12:]) == account
+	if isWithdrawalAddress {
+		log.Infof("Withdrawal address: yes, %v is the withdrawal address of validator %s (credentials %v).", account, name, hexutil.Encode(credentials))
+	} else {
+		log.Infof("Withdrawal address: no, validator %s has withdrawal credentials %v.", name, hexutil.Encode(credentials))
+	}
+
+	deposits, err := depositsFor(validator.Validator.PublicKey[:], fromBlock, toBlock)
+	if err != nil {
+		return err
+	}
+	madeDeposit := false
+	for _, d := range deposits {
+		if d.Sender == account {
+			madeDeposit = true
+			log.Infof("Depositor: yes, %v deposited %v Gwei for validator %s in transaction %v at block %d.", account, d.Amount, name, d.TxHash.Hex(), d.Block)
+		} else {
+			log.Infof("Deposit of %v Gwei for validator %s in transaction %v at block %d was made by %v.", d.Amount, name, d.TxHash.Hex(), d.Block, d.Sender)
+		}
+	}
+	if len(deposits) == 0 {
+		log.Infof("Depositor: no deposits for validator %s were found in the block range.", name)
+	} else if !madeDeposit {
+		log.Infof("Depositor: no, %v did not make any of the %d deposit(s) for validator %s.", account, len(deposits), name)
+	}
+	if isWithdrawalAddress || madeDeposit {
+		log.Infof("%v controls validator %s.", account, name)
+	} else {
+		log.Warnf("Could not find evidence that %v controls validator %s.", account, name)
+	}
+	return nil
+}
+
+// depositsFor scans the deposit contract logs in the block range for deposits for the validator public key.
+func depositsFor(pubKey []byte, fromBlock uint64, toBlock uint64) ([]*deposit, error) {
+	specResponse, err := specProvider.Spec(blockchain.Ctx, &api.SpecOpts{})
+	if err != nil {
+		return nil, util.WrapError(err, "failed to obtain spec")
+	}
+	contract, ok := specResponse.Data["DEPOSIT_CONTRACT_ADDRESS"].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("DEPOSIT_CONTRACT_ADDRESS not found in spec")
+	}
+	if toBlock == 0 {
+		if toBlock, err = blockchain.ExecutionClient.BlockNumber(blockchain.Ctx); err != nil {
+			return nil, util.WrapError(err, "could not get latest block number")
+		}
+	}
+	chainID, err := blockchain.ExecutionClient.ChainID(blockchain.Ctx)
+	if err != nil {
+		return nil, util.WrapError(err, "could not get chain id")
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	arguments, err := depositEventArguments()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Scanning deposit contract %v logs from block %d to %d...", common.BytesToAddress(contract), fromBlock, toBlock)
+	deposits := make([]*deposit, 0)
+	for start := fromBlock; start <= toBlock; start += depositLogsChunk {
+		end := start + depositLogsChunk - 1
+		if end > toBlock {
+			end = toBlock
+		}
+		logs, err := blockchain.ExecutionClient.FilterLogs(blockchain.Ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(start),
+			ToBlock:   new(big.Int).SetUint64(end),
+			Addresses: []common.Address{common.BytesToAddress(contract)},
+			Topics:    [][]common.Hash{{depositEventTopic}},
+		})
+		if err != nil {
+			return nil, util.WrapError(err, "could not get deposit logs for blocks %d to %d", start, end)
+		}
+		for _, l := range logs {
+			values, err := arguments.Unpack(l.Data)
+			if err != nil || len(values) != 5 {
+				log.Warnf("Could not decode deposit log in transaction %v: %v", l.TxHash.Hex(), err)
+				continue
+			}
+			if !bytes.Equal(values[0].([]byte), pubKey) {
+				continue
+			}
+			tx, _, err := blockchain.ExecutionClient.TransactionByHash(blockchain.Ctx, l.TxHash)
+			if err != nil {
+				return nil, util.WrapError(err, "could not get deposit transaction %v", l.TxHash.Hex())
+			}
+			sender, err := types.Sender(signer, tx)
+			if err != nil {
+				return nil, util.WrapError(err, "could not get sender of deposit transaction %v", l.TxHash.Hex())
+			}
+			deposits = append(deposits, &deposit{
+				Block:       l.BlockNumber,
+				TxHash:      l.TxHash,
+				PubKey:      values[0].([]byte),
+				Sender:      sender,
+				Credentials: values[1].([]byte),
+				// The amount is a little-endian Gwei value.
+				Amount: littleEndianUint64(values[2].([]byte)),
+			})
+		}
+		if end == toBlock {
+			break
+		}
+	}
+	return deposits, nil
+}
+
+func depositEventArguments() (abi.Arguments, error) {
+	bytesType, err := abi.NewType("bytes", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	arguments := abi.Arguments{}
+	for _, name := range strings.Split("pubkey,withdrawal_credentials,amount,signature,index", ",") {
+		arguments = append(arguments, abi.Argument{Name: name, Type: bytesType})
+	}
+	return arguments, nil
+}
+
+func littleEndianUint64(b []byte) uint64 {
+	v := uint64(0)
+	for i := len(b) - 1; i >= 0; i-- {
+		v = v<<8 | uint64(b[i])
+	}
+	return v
+}
@@ -0,0 +1,92 @@
+package validators
+
+import (
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/allisterb/strac/blockchain"
+	"github.com/allisterb/strac/util"
+)
+
+// Income projects the annual income of the validators from the rewards they earned over the most recent finalized
+// epochs i.e. the change in their balances plus any withdrawals made during the observation window. If fiat is specified
+// the projection is also converted to the fiat currency, using the price if given or else fetching the current price of the token.
+func Income(validatorsStr []string, numEpochs uint64, fiat string, priceTokenID string, price float64) error {
+	if len(validatorsStr) == 0 {
+		return fmt.Errorf("at least 1 validator index or public key must be specified to project income for")
+	}
+	if numEpochs == 0 {
+		return fmt.Errorf("the observation window must be at least 1 epoch")
+	}
+	if err := Init(); err != nil {
+		return err
+	}
+	endEpoch, err := finalizedEpoch()
+	if err != nil {
+		return err
+	}
+	if uint64(endEpoch) < numEpochs {
+		return fmt.Errorf("only %d finalized epochs are available", endEpoch)
+	}
+	startEpoch := endEpoch - phase0.Epoch(numEpochs)
+	firstSlot, lastSlot := chainTime.FirstSlotOfEpoch(startEpoch), chainTime.FirstSlotOfEpoch(endEpoch)
+
+	startBalances, err := validatorBalances(validatorsStr, firstSlot)
+	if err != nil {
+		return err
+	}
+	endBalances, err := validatorBalances(validatorsStr, lastSlot)
+	if err != nil {
+		return err
+	}
+	// Only validators that existed for the whole window are included so deposits are not counted as rewards.
+	indices := make(map[phase0.ValidatorIndex]struct{})
+	for index := range endBalances {
+		if _, exists := startBalances[index]; exists {
+			indices[index] = struct{}{}
+		}
+	}
+	log.Infof("Scanning slots %d to %d for withdrawals...", firstSlot+1, lastSlot)
+	withdrawals, err := withdrawalsInRange(indices, firstSlot+1, lastSlot)
+	if err != nil {
+		return err
+	}
+
+	rewards := int64(0)
+	for index := range indices {
+		rewards += int64(endBalances[index]) - int64(startBalances[index])
+	}
+	for _, w := range withdrawals {
+		rewards += int64(w.Amount)
+	}
+	window := chainTime.StartOfEpoch(endEpoch).Sub(chainTime.StartOfEpoch(startEpoch))
+	annual := float64(rewards) / 1e9 * (365.25 * 24 * 3600) / window.Seconds()
+
+	log.Infof("Observation window: epochs %d to %d (%v), %d validator(s).", startEpoch, endEpoch, window, len(indices))
+	log.Infof("Rewards earned in the window: %.9f %s.", float64(rewards)/1e9, util.NativeSymbol)
+	log.Infof("Projected annual income: %.4f %s.", annual, util.NativeSymbol)
+	if fiat != "" {
+		if price == 0 {
+			if price, err = util.FetchPrice(priceTokenID, fiat); err != nil {
+				return err
+			}
+		}
+		log.Infof("Projected annual income: %.2f %s at a price of %v %s per %s.", annual*price, fiat, price, fiat, util.NativeSymbol)
+	}
+	log.Infof("This is a projection based on the observed rewards and is not a guarantee of future income.")
+	return nil
+}
+
+// validatorBalances returns the balances of the validators in Gwei at the given slot.
+func validatorBalances(validatorsStr []string, slot phase0.Slot) (map[phase0.ValidatorIndex]phase0.Gwei, error) {
+	validators, err := parseValidators(blockchain.Ctx, validatorsStr, fmt.Sprintf("%d", slot))
+	if err != nil {
+		return nil, err
+	}
+	balances := make(map[phase0.ValidatorIndex]phase0.Gwei)
+	for _, validator := range validators {
+		balances[validator.Index] = validator.Balance
+	}
+	return balances, nil
+}
@@ -0,0 +1,68 @@
+package validators
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/allisterb/strac/util"
+)
+
+// labels maps validator indices or lower-case public keys to human-readable labels.
+var labels = make(map[string]string)
+
+// LoadLabels loads a JSON or CSV file mapping validator indices or public keys to human-readable labels.
+// A JSON file should contain a single object e.g. {"1234": "home-01"}, and a CSV file should contain rows of validator,label.
+func LoadLabels(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return util.WrapError(err, "could not open labels file %s", path)
+	}
+	defer f.Close()
+
+	entries := make(map[string]string)
+	if strings.ToLower(filepath.Ext(path)) == ".csv" {
+		reader := csv.NewReader(f)
+		reader.FieldsPerRecord = 2
+		reader.TrimLeadingSpace = true
+		records, err := reader.ReadAll()
+		if err != nil {
+			return util.WrapError(err, "could not parse labels CSV file %s", path)
+		}
+		for _, record := range records {
+			entries[record[0]] = record[1]
+		}
+	} else if err := json.NewDecoder(f).Decode(&entries); err != nil {
+		return util.WrapError(err, "could not parse labels JSON file %s", path)
+	}
+
+	for k, v := range entries {
+		labels[strings.ToLower(strings.TrimSpace(k))] = strings.TrimSpace(v)
+	}
+	log.Debugf("Loaded %d validator labels from %s.", len(entries), path)
+	return nil
+}
+
+// labelFor returns the label for the validator with the given index or public key, or an empty string if there is none.
+func labelFor(index phase0.ValidatorIndex, pubKey string) string {
+	if label, exists := labels[fmt.Sprintf("%d", index)]; exists {
+		return label
+	}
+	if pubKey != "" {
+		return labels[strings.ToLower(pubKey)]
+	}
+	return ""
+}
+
+// validatorName formats a validator index together with its label if it has one.
+func validatorName(index phase0.ValidatorIndex, pubKey string) string {
+	if label := labelFor(index, pubKey); label != "" {
+		return fmt.Sprintf("%d [%s]", index, label)
+	}
+	return fmt.Sprintf("%d", index)
+}
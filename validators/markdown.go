@@ -0,0 +1,70 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// markdownSummary renders the participation of each epoch and the faults of the validators as Markdown tables.
+func markdownSummary(summaries []*validatorSummary) string {
+	builder := strings.Builder{}
+	builder.WriteString("| Epoch | Active | Participating | Participation rate | Missed proposals |\n")
+	builder.WriteString("|---:|---:|---:|---:|---:|\n")
+	for _, summary := range summaries {
+		if summary.TextSummary == "" {
+			continue
+		}
+		missed := 0
+		for _, p := range summary.Proposals {
+			if !p.Block {
+				missed++
+			}
+		}
+		rate := 0.0
+		if summary.ActiveValidators > 0 {
+			rate = 100 * float64(summary.ParticipatingValidators) / float64(summary.ActiveValidators)
+		}
+		builder.WriteString(fmt.Sprintf("| %d | %d | %d | %.2f%% | %d |\n", summary.Epoch, summary.ActiveValidators, summary.ParticipatingValidators, rate, missed))
+	}
+
+	faults := strings.Builder{}
+	for _, summary := range summaries {
+		name := func(index phase0.ValidatorIndex) string {
+			return validatorName(index, pubKeyOf(summary.Validators, index))
+		}
+		for _, p := range summary.Proposals {
+			if !p.Block {
+				faults.WriteString(fmt.Sprintf("| %d | %s | Missed proposal | %d | | |\n", summary.Epoch, name(p.Proposer), p.Slot))
+			}
+		}
+		for _, v := range summary.NonParticipatingValidators {
+			faults.WriteString(fmt.Sprintf("| %d | %s | Non-participating | %d | %d | |\n", summary.Epoch, name(v.Validator), v.Slot, v.Committee))
+		}
+		for _, list := range []struct {
+			fault      string
+			validators []*validatorFault
+		}{
+			{"Incorrect head", summary.IncorrectHeadValidators},
+			{"Untimely head", summary.UntimelyHeadValidators},
+			{"Untimely source", summary.UntimelySourceValidators},
+			{"Incorrect target", summary.IncorrectTargetValidators},
+			{"Untimely target", summary.UntimelyTargetValidators},
+		} {
+			for _, v := range list.validators {
+				distance := ""
+				if v.InclusionDistance > 0 {
+					distance = fmt.Sprintf("%d", v.InclusionDistance)
+				}
+				faults.WriteString(fmt.Sprintf("| %d | %s | %s | %d | %d | %s |\n", summary.Epoch, name(v.Validator), list.fault, v.AttestationData.Slot, v.AttestationData.Index, distance))
+			}
+		}
+	}
+	if faults.Len() > 0 {
+		builder.WriteString("\n| Epoch | Validator | Fault | Slot | Committee | Inclusion distance |\n")
+		builder.WriteString("|---:|---|---|---:|---:|---:|\n")
+		builder.WriteString(faults.String())
+	}
+	return builder.String()
+}
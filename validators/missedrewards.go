@@ -0,0 +1,161 @@
+package validators
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/allisterb/strac/util"
+)
+
+// missedRewards holds the estimated rewards in Gwei a validator lost to each category of missed duty.
+type missedRewards struct {
+	Index             phase0.ValidatorIndex
+	MissedAttestation int
+	MissedSource      int
+	MissedTarget      int
+	MissedHead        int
+	MissedProposal    int
+	AttestationLoss   uint64
+	SourceLoss        uint64
+	TargetLoss        uint64
+	HeadLoss          uint64
+	ProposalLoss      uint64
+}
+
+func (m *missedRewards) total() uint64 {
+	return m.AttestationLoss + m.SourceLoss + m.TargetLoss + m.HeadLoss + m.ProposalLoss
+}
+
+// MissedRewards estimates for each of the validators the rewards lost in the epoch range to missed attestations,
+// late or incorrect attestation votes and missed proposals, using the fault lists of the epoch summaries.
+//
+// The estimates use the altair reward model with the current total active balance for the whole range, and assume every
+// other validator performed its duties:
+//   - a missed attestation forfeits the source, target and head rewards and incurs the source and target penalties;
+//   - a late source or a late or incorrect target forfeits that reward and incurs the penalty of the same size;
+//   - a late or incorrect head forfeits the head reward only as there is no head penalty;
+//   - a missed proposal forfeits the proposer's share of the rewards of the attestations and sync contributions
+//     the block would have included, which is PROPOSER_WEIGHT / WEIGHT_DENOMINATOR of the base rewards of a slot.
+func MissedRewards(validatorsStr []string, stateID string, start string, end string, num string) error {
+	if len(validatorsStr) == 0 {
+		return fmt.Errorf("at least 1 validator index or public key must be specified to estimate missed rewards for")
+	}
+	if err := Init(); err != nil {
+		return err
+	}
+	startEpoch, endEpoch, err := parseEpochRange(start, end, num)
+	if err != nil {
+		return err
+	}
+	if current := chainTime.CurrentEpoch(); endEpoch > current {
+		if startEpoch > current {
+			return fmt.Errorf("the start epoch %v is beyond the current epoch %v", startEpoch, current)
+		}
+		log.Warnf("Skipping epochs %v to %v which are beyond the current epoch %v.", current+1, endEpoch, current)
+		endEpoch = current
+	}
+	params, err := currentRewardParameters()
+	if err != nil {
+		return err
+	}
+	proposalReward := params.totalBaseRewards() * proposerWeight / weightDenominator / params.SlotsPerEpoch
+
+	log.Infof("fetching validator(s) faults for start epoch: %v, end epoch: %v.", startEpoch, endEpoch)
+	results := make(map[phase0.ValidatorIndex]*missedRewards)
+	for _, summary := range epochSummaries(validatorsStr, stateID, startEpoch, endEpoch, false) {
+		if summary.TextSummary == "" {
+			continue
+		}
+		baseRewards := make(map[phase0.ValidatorIndex]uint64)
+		for _, validator := range summary.Validators {
+			baseRewards[validator.Index] = params.baseReward(validator.Validator.EffectiveBalance)
+			if _, exists := results[validator.Index]; !exists {
+				results[validator.Index] = &missedRewards{Index: validator.Index}
+			}
+		}
+		for _, v := range summary.NonParticipatingValidators {
+			m := results[v.Validator]
+			m.MissedAttestation++
+			m.AttestationLoss += baseRewards[v.Validator] * (2*timelySourceWeight + 2*timelyTargetWeight + timelyHeadWeight) / weightDenominator
+		}
+		// A validator can be in both the incorrect and the untimely list for the same vote so only count it once per epoch.
+		for _, index := range faultIndices(summary.UntimelySourceValidators) {
+			m := results[index]
+			m.MissedSource++
+			m.SourceLoss += baseRewards[index] * 2 * timelySourceWeight / weightDenominator
+		}
+		for _, index := range faultIndices(summary.IncorrectTargetValidators, summary.UntimelyTargetValidators) {
+			m := results[index]
+			m.MissedTarget++
+			m.TargetLoss += baseRewards[index] * 2 * timelyTargetWeight / weightDenominator
+		}
+		for _, index := range faultIndices(summary.IncorrectHeadValidators, summary.UntimelyHeadValidators) {
+			m := results[index]
+			m.MissedHead++
+			m.HeadLoss += baseRewards[index] * timelyHeadWeight / weightDenominator
+		}
+		for _, p := range summary.Proposals {
+			if m, exists := results[p.Proposer]; exists && !p.Block {
+				m.MissedProposal++
+				m.ProposalLoss += proposalReward
+			}
+		}
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("could not retrieve validator data for epochs %v to %v", startEpoch, endEpoch)
+	}
+
+	sorted := make([]*missedRewards, 0, len(results))
+	for _, m := range results {
+		sorted = append(sorted, m)
+	}
+	sort.Slice(sorted, func(i int, j int) bool {
+		return sorted[i].Index < sorted[j].Index
+	})
+	totals := &missedRewards{}
+	log.Infof("Estimated rewards lost to missed duties for epochs %v to %v (estimates only):", startEpoch, endEpoch)
+	for _, m := range sorted {
+		log.Infof("Validator %s: missed attestations: %d (est. -%s), late source: %d (est. -%s), late or incorrect target: %d (est. -%s), late or incorrect head: %d (est. -%s), missed proposals: %d (est. -%s), estimated total: -%s.",
+			validatorName(m.Index, ""), m.MissedAttestation, gweiString(m.AttestationLoss), m.MissedSource, gweiString(m.SourceLoss),
+			m.MissedTarget, gweiString(m.TargetLoss), m.MissedHead, gweiString(m.HeadLoss), m.MissedProposal, gweiString(m.ProposalLoss), gweiString(m.total()))
+		totals.AttestationLoss += m.AttestationLoss
+		totals.SourceLoss += m.SourceLoss
+		totals.TargetLoss += m.TargetLoss
+		totals.HeadLoss += m.HeadLoss
+		totals.ProposalLoss += m.ProposalLoss
+	}
+	log.Infof("Estimated total lost: missed attestations: -%s, late source: -%s, late or incorrect target: -%s, late or incorrect head: -%s, missed proposals: -%s, total: -%s.",
+		gweiString(totals.AttestationLoss), gweiString(totals.SourceLoss), gweiString(totals.TargetLoss), gweiString(totals.HeadLoss), gweiString(totals.ProposalLoss), gweiString(totals.total()))
+	return nil
+}
+
+// faultIndices returns the distinct validator indices in the fault lists.
+func faultIndices(lists ...[]*validatorFault) []phase0.ValidatorIndex {
+	seen := make(map[phase0.ValidatorIndex]struct{})
+	indices := make([]phase0.ValidatorIndex, 0)
+	for _, list := range lists {
+		for _, fault := range list {
+			if _, exists := seen[fault.Validator]; !exists {
+				seen[fault.Validator] = struct{}{}
+				indices = append(indices, fault.Validator)
+			}
+		}
+	}
+	return indices
+}
+
+// gweiString formats an amount in Gwei as an amount of the native token.
+func gweiString(gwei uint64) string {
+	return util.FormatUnits(new(big.Int).SetUint64(gwei), 9, false) + " " + util.NativeSymbol
+}
+
+// signedGweiString formats a signed amount in Gwei as an amount of the native token with an explicit sign.
+func signedGweiString(gwei int64) string {
+	if gwei < 0 {
+		return "-" + gweiString(uint64(-gwei))
+	}
+	return "+" + gweiString(uint64(gwei))
+}
@@ -0,0 +1,129 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/allisterb/strac/blockchain"
+	"github.com/allisterb/strac/util"
+)
+
+// Monitor tracks the attestation participation of the validators each epoch and logs an event only when a validator
+// transitions between participating and not participating, until interrupted. Each epoch is evaluated once all the
+// attestations for it can have been included i.e. 2 epochs behind the current epoch.
+func Monitor(validatorsStr []string) error {
+	if len(validatorsStr) == 0 {
+		return fmt.Errorf("at least 1 validator index or public key must be specified to monitor")
+	}
+	if err := Init(); err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	defer func(c context.Context) { blockchain.Ctx = c }(blockchain.Ctx)
+	epochDuration := chainTime.SlotDuration() * time.Duration(chainTime.SlotsPerEpoch())
+
+	log.Infof("Monitoring participation of validator(s) %v. Press Ctrl-C to stop.", validatorsStr)
+	participating := make(map[phase0.ValidatorIndex]bool)
+	for {
+		current := chainTime.CurrentEpoch()
+		if current >= 2 {
+			// Each epoch gets its own timeout as the monitor may run much longer than the command timeout.
+			epochCtx, cancel := context.WithTimeout(ctx, epochDuration)
+			blockchain.Ctx = epochCtx
+			if err := monitorEpoch(validatorsStr, current-2, participating); err != nil && ctx.Err() == nil {
+				log.Errorf("Could not get participation for epoch %d: %v", current-2, err)
+			}
+			cancel()
+		}
+		if err := chainTime.WaitUntilEpoch(ctx, current+1); err != nil {
+			log.Infof("Stopped monitoring validator(s).")
+			return nil
+		}
+	}
+}
+
+// monitorEpoch updates the last-known participation of each validator with the epoch's summary and logs each transition.
+func monitorEpoch(validatorsStr []string, epoch phase0.Epoch, participating map[phase0.ValidatorIndex]bool) error {
+	// Epoch summaries are computed holding a slot of util.Concurrency, see blockAttestations.
+	util.Concurrency.Acquire()
+	summary, err := EpochSummary(validatorsStr, "head", strconv.FormatUint(uint64(epoch), 10), false)
+	util.Concurrency.Release()
+	if err != nil {
+		return err
+	}
+	current := make(map[phase0.ValidatorIndex]bool)
+	for _, v := range summary.AttestingValidators {
+		current[v.Validator.Index] = true
+	}
+	for _, v := range summary.NonParticipatingValidators {
+		current[v.Validator] = false
+	}
+	first := len(participating) == 0
+	for index, now := range current {
+		before, known := participating[index]
+		participating[index] = now
+		if !known || before == now {
+			continue
+		}
+		if now {
+			log.Infof("Epoch %d: validator %s resumed participating.", epoch, validatorName(index, pubKeyOf(summary.Validators, index)))
+		} else {
+			log.Warnf("Epoch %d: validator %s stopped participating.", epoch, validatorName(index, pubKeyOf(summary.Validators, index)))
+		}
+	}
+	if first {
+		log.Infof("Epoch %d: %d of %d active validator(s) participating.", epoch, len(summary.AttestingValidators), len(current))
+	}
+	return nil
+}
+
+// MonitorFinality checks the finalized checkpoint each epoch and warns when the number of epochs since finalization
+// exceeds the threshold, until interrupted.
+func MonitorFinality(threshold uint64) error {
+	if err := Init(); err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	defer func(c context.Context) { blockchain.Ctx = c }(blockchain.Ctx)
+	epochDuration := chainTime.SlotDuration() * time.Duration(chainTime.SlotsPerEpoch())
+
+	log.Infof("Monitoring finality with a threshold of %d epoch(s). Press Ctrl-C to stop.", threshold)
+	stalled := false
+	for {
+		current := chainTime.CurrentEpoch()
+		epochCtx, cancel := context.WithTimeout(ctx, epochDuration)
+		blockchain.Ctx = epochCtx
+		finalized, err := finalizedEpoch()
+		cancel()
+		if err != nil && ctx.Err() == nil {
+			log.Errorf("Could not get finality at epoch %d: %v", current, err)
+		} else if err == nil {
+			gap := uint64(0)
+			if current > finalized {
+				gap = uint64(current - finalized)
+			}
+			if gap > threshold {
+				log.Warnf("Epoch %d: last finalized epoch is %d, %d epoch(s) ago. The chain is struggling to finalize.", current, finalized, gap)
+				stalled = true
+			} else if stalled {
+				log.Infof("Epoch %d: finality recovered, last finalized epoch is %d.", current, finalized)
+				stalled = false
+			} else {
+				log.Infof("Epoch %d: last finalized epoch is %d.", current, finalized)
+			}
+		}
+		if err := chainTime.WaitUntilEpoch(ctx, current+1); err != nil {
+			log.Infof("Stopped monitoring finality.")
+			return nil
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package validators
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/allisterb/strac/util"
+)
+
+// writePerfCSV writes a CSV file with one row per slot of the attestation counts of the validators, followed by a
+// section with one row per validator of its participation and faults across all the epochs.
+func writePerfCSV(summaries []*validatorSummary, out string) error {
+	f, err := os.Create(out)
+	if err != nil {
+		return util.WrapError(err, "could not create file %s", out)
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	if err := w.Write([]string{"epoch", "slot", "expected", "included", "correct_head", "timely_head", "correct_target", "timely_target", "timely_source"}); err != nil {
+		return err
+	}
+	for _, summary := range summaries {
+		if summary.TextSummary == "" {
+			continue
+		}
+		for _, s := range summary.Slots {
+			a := s.Attestations
+			if a == nil {
+				a = &slotAttestations{}
+			}
+			if err := w.Write([]string{
+				strconv.FormatUint(uint64(summary.Epoch), 10), strconv.FormatUint(uint64(s.Slot), 10),
+				strconv.Itoa(a.Expected), strconv.Itoa(a.Included), strconv.Itoa(a.CorrectHead), strconv.Itoa(a.TimelyHead),
+				strconv.Itoa(a.CorrectTarget), strconv.Itoa(a.TimelyTarget), strconv.Itoa(a.TimelySource),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	indices, participation := validatorParticipations(summaries)
+	// A blank row separates the per-slot and per-validator sections.
+	if err := w.Write([]string{}); err != nil {
+		return err
+	}
+	if err := w.Write([]string{"validator", "duties", "missed", "participation_rate", "incorrect_head", "untimely_head", "untimely_source", "incorrect_target", "untimely_target"}); err != nil {
+		return err
+	}
+	written := 0
+	for _, index := range indices {
+		p := participation[index]
+		if p.Duties == 0 {
+			continue
+		}
+		written++
+		rate := float64(p.Duties-p.Missed) / float64(p.Duties)
+		if err := w.Write([]string{
+			strconv.FormatUint(uint64(index), 10), strconv.Itoa(p.Duties), strconv.Itoa(p.Missed), fmt.Sprintf("%.4f", rate),
+			strconv.Itoa(p.IncorrectHead), strconv.Itoa(p.UntimelyHead), strconv.Itoa(p.UntimelySource),
+			strconv.Itoa(p.IncorrectTarget), strconv.Itoa(p.UntimelyTarget),
+		}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return util.WrapError(err, "could not write file %s", out)
+	}
+	if err := f.Close(); err != nil {
+		return util.WrapError(err, "could not write file %s", out)
+	}
+	log.Infof("Wrote %d validator(s) performance to %s.", written, out)
+	return nil
+}
@@ -0,0 +1,186 @@
+package validators
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	api "github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/allisterb/strac/blockchain"
+	"github.com/allisterb/strac/util"
+)
+
+type proposerEfficiency struct {
+	Index    phase0.ValidatorIndex
+	Duties   int
+	Produced int
+	// Fullness is the sum of the fullness of each block produced, between 0 and 1 per block.
+	Fullness float64
+}
+
+// ProposerEfficiency reports for each proposal duty of the validators in the epoch range whether the block was produced
+// and how full it was relative to the maximum attestations and sync committee bits, and an efficiency percentage per validator.
+// Missed proposals count as empty blocks.
+func ProposerEfficiency(validatorsStr []string, start string, end string, num string) error {
+	if len(validatorsStr) == 0 {
+		return fmt.Errorf("at least 1 validator index or public key must be specified to retrieve proposer efficiency for")
+	}
+	if err := Init(); err != nil {
+		return err
+	}
+	startEpoch, endEpoch, err := parseEpochRange(start, end, num)
+	if err != nil {
+		return err
+	}
+	validators, err := parseValidators(blockchain.Ctx, validatorsStr, "head")
+	if err != nil {
+		return err
+	}
+	maxAttestations, err := maxAttestationsPerBlock()
+	if err != nil {
+		return err
+	}
+	validatorsByIndex := make(map[phase0.ValidatorIndex]*apiv1.Validator)
+	efficiencies := make(map[phase0.ValidatorIndex]*proposerEfficiency)
+	for _, validator := range validators {
+		validatorsByIndex[validator.Index] = validator
+		efficiencies[validator.Index] = &proposerEfficiency{Index: validator.Index}
+	}
+
+	proposals, err := proposalsInRange(validatorsByIndex, startEpoch, endEpoch)
+	if err != nil {
+		return err
+	}
+	for _, p := range proposals {
+		e := efficiencies[p.Proposer]
+		e.Duties++
+		if !p.Block {
+			log.Infof("Slot %d: validator %s missed the proposal.", p.Slot, validatorName(p.Proposer, pubKeyOf(validators, p.Proposer)))
+			continue
+		}
+		e.Produced++
+		fullness := blockFullness(p, maxAttestations)
+		e.Fullness += fullness
+		log.Infof("Slot %d: validator %s produced a block with %d/%d attestations and %d/%d sync bits (%.1f%% full).",
+			p.Slot, validatorName(p.Proposer, pubKeyOf(validators, p.Proposer)), p.Attestations, maxAttestations, p.SyncBits, p.SyncSize, fullness*100)
+	}
+
+	results := make([]*proposerEfficiency, 0)
+	for _, e := range efficiencies {
+		if e.Duties > 0 {
+			results = append(results, e)
+		}
+	}
+	sort.Slice(results, func(i int, j int) bool {
+		return results[i].Index < results[j].Index
+	})
+	if len(results) == 0 {
+		log.Infof("None of the validator(s) had proposal duties for epochs %v to %v.", startEpoch, endEpoch)
+		return nil
+	}
+	for _, e := range results {
+		log.Infof("Validator %s: %d of %d proposals produced, efficiency %.1f%%.",
+			validatorName(e.Index, pubKeyOf(validators, e.Index)), e.Produced, e.Duties, e.Fullness*100/float64(e.Duties))
+	}
+	return nil
+}
+
+// ProposalScorecard reports for each of the validators the number of proposal duties it had in the epoch range,
+// the number of blocks it produced and its proposal success rate.
+func ProposalScorecard(validatorsStr []string, start string, end string, num string) error {
+	if len(validatorsStr) == 0 {
+		return fmt.Errorf("at least 1 validator index or public key must be specified to retrieve proposals for")
+	}
+	if err := Init(); err != nil {
+		return err
+	}
+	startEpoch, endEpoch, err := parseEpochRange(start, end, num)
+	if err != nil {
+		return err
+	}
+	validators, err := parseValidators(blockchain.Ctx, validatorsStr, "head")
+	if err != nil {
+		return err
+	}
+	validatorsByIndex := make(map[phase0.ValidatorIndex]*apiv1.Validator)
+	for _, validator := range validators {
+		validatorsByIndex[validator.Index] = validator
+	}
+	proposals, err := proposalsInRange(validatorsByIndex, startEpoch, endEpoch)
+	if err != nil {
+		return err
+	}
+	duties := make(map[phase0.ValidatorIndex]int)
+	produced := make(map[phase0.ValidatorIndex]int)
+	for _, p := range proposals {
+		duties[p.Proposer]++
+		if p.Block {
+			produced[p.Proposer]++
+		}
+	}
+	sort.Slice(validators, func(i int, j int) bool {
+		return validators[i].Index < validators[j].Index
+	})
+
+	log.Infof("Proposal scorecard for epochs %v to %v:", startEpoch, endEpoch)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VALIDATOR\tEXPECTED\tPRODUCED\tMISSED\tSUCCESS RATE")
+	for _, validator := range validators {
+		rate := "n/a"
+		if duties[validator.Index] > 0 {
+			rate = fmt.Sprintf("%.1f%%", 100*float64(produced[validator.Index])/float64(duties[validator.Index]))
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\n", validatorName(validator.Index, hexutil.Encode(validator.Validator.PublicKey[:])),
+			duties[validator.Index], produced[validator.Index], duties[validator.Index]-produced[validator.Index], rate)
+	}
+	return w.Flush()
+}
+
+// proposalsInRange returns the proposal duties of the validators in the epoch range up to the current slot
+// and whether a block was produced for each.
+func proposalsInRange(validatorsByIndex map[phase0.ValidatorIndex]*apiv1.Validator, startEpoch phase0.Epoch, endEpoch phase0.Epoch) ([]*epochProposal, error) {
+	log.Infof("fetching proposals for start epoch: %v, end epoch: %v.", startEpoch, endEpoch)
+	proposals := make([]*epochProposal, 0)
+	for epoch := startEpoch; epoch <= endEpoch; epoch++ {
+		summary := &validatorSummary{Epoch: epoch}
+		if err := processProposerDuties(validatorsByIndex, summary); err != nil {
+			return nil, util.WrapError(err, "could not process proposer duties for epoch %d", epoch)
+		}
+		for _, p := range summary.Proposals {
+			if p.Slot <= chainTime.CurrentSlot() {
+				proposals = append(proposals, p)
+			}
+		}
+	}
+	return proposals, nil
+}
+
+// blockFullness returns how full a produced block is between 0 and 1, averaging the attestation and sync committee
+// fullness where the block has a sync aggregate.
+func blockFullness(p *epochProposal, maxAttestations int) float64 {
+	fullness := float64(p.Attestations) / float64(maxAttestations)
+	if fullness > 1 {
+		fullness = 1
+	}
+	if p.SyncSize > 0 {
+		fullness = (fullness + float64(p.SyncBits)/float64(p.SyncSize)) / 2
+	}
+	return fullness
+}
+
+func maxAttestationsPerBlock() (int, error) {
+	specResponse, err := specProvider.Spec(blockchain.Ctx, &api.SpecOpts{})
+	if err != nil {
+		return 0, util.WrapError(err, "failed to obtain spec")
+	}
+	v, ok := specResponse.Data["MAX_ATTESTATIONS"].(uint64)
+	if !ok || v == 0 {
+		return 0, fmt.Errorf("MAX_ATTESTATIONS not found in spec")
+	}
+	return int(v), nil
+}
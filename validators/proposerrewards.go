@@ -0,0 +1,55 @@
+package validators
+
+import (
+	"sync"
+)
+
+var rewardParams *rewardParameters
+var rewardParamsLock sync.Mutex
+
+// cachedRewardParameters returns the reward parameters, fetching them only once as this needs the entire validator set.
+func cachedRewardParameters() (*rewardParameters, error) {
+	rewardParamsLock.Lock()
+	defer rewardParamsLock.Unlock()
+	if rewardParams != nil {
+		return rewardParams, nil
+	}
+	params, err := currentRewardParameters()
+	if err != nil {
+		return nil, err
+	}
+	rewardParams = params
+	return rewardParams, nil
+}
+
+// estimateProposalRewards estimates the proposer reward of each proposal in the summary from the attestations and sync
+// committee bits included in the block, using the altair reward formulas and the current total active balance:
+//   - each included attestation vote earns the proposer the base reward times the timely source, target and head
+//     weights divided by (WEIGHT_DENOMINATOR - PROPOSER_WEIGHT) * WEIGHT_DENOMINATOR / PROPOSER_WEIGHT, using the
+//     average base reward and assuming every vote is new and timely, so this is an upper bound;
+//   - each included sync committee bit earns the proposer the participant reward times PROPOSER_WEIGHT divided by
+//     WEIGHT_DENOMINATOR - PROPOSER_WEIGHT.
+//
+// A missed proposal earns nothing and its lost reward is estimated as the proposer's share of the base rewards of a
+// slot as in MissedRewards.
+func estimateProposalRewards(summary *validatorSummary) {
+	if len(summary.Proposals) == 0 {
+		return
+	}
+	params, err := cachedRewardParameters()
+	if err != nil {
+		log.Warnf("Could not estimate proposer rewards for epoch %d: %v", summary.Epoch, err)
+		return
+	}
+	averageBaseReward := params.totalBaseRewards() / params.ActiveValidators
+	attestationReward := averageBaseReward * (timelySourceWeight + timelyTargetWeight + timelyHeadWeight) * proposerWeight / ((weightDenominator - proposerWeight) * weightDenominator)
+	participantReward := params.totalBaseRewards() * syncRewardWeight / weightDenominator / params.SlotsPerEpoch / params.SyncCommitteeSize
+	syncReward := participantReward * proposerWeight / (weightDenominator - proposerWeight)
+	for _, p := range summary.Proposals {
+		if p.Block {
+			p.Reward = uint64(p.AttestingBits)*attestationReward + uint64(p.SyncBits)*syncReward
+		} else {
+			p.LostReward = params.totalBaseRewards() * proposerWeight / weightDenominator / params.SlotsPerEpoch
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package validators
+
+import (
+	"fmt"
+	"net/http"
+
+	api "github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+
+	"github.com/allisterb/strac/blockchain"
+	"github.com/allisterb/strac/util"
+)
+
+// retry calls fn with up to util.Retries attempts. A 404 is not retried as it means the requested data e.g. a block
+// doesn't exist.
+func retry(fn func() error) error {
+	return util.RetryWithBackoff(blockchain.Ctx, util.Retries, func() error {
+		err := fn()
+		var apiErr *api.Error
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return util.Permanent(err)
+		}
+		return err
+	})
+}
+
+// signedBeaconBlock obtains the block at the slot, retrying transient errors.
+func signedBeaconBlock(slot phase0.Slot) (*api.Response[*spec.VersionedSignedBeaconBlock], error) {
+	var response *api.Response[*spec.VersionedSignedBeaconBlock]
+	err := retry(func() error {
+		var err error
+		response, err = blocksProvider.SignedBeaconBlock(blockchain.Ctx, &api.SignedBeaconBlockOpts{
+			Block: fmt.Sprintf("%d", slot),
+		})
+		return err
+	})
+	return response, err
+}
+
+// proposerDuties obtains the proposer duties of the epoch, retrying transient errors.
+func proposerDuties(epoch phase0.Epoch) (*api.Response[[]*apiv1.ProposerDuty], error) {
+	var response *api.Response[[]*apiv1.ProposerDuty]
+	err := retry(func() error {
+		var err error
+		response, err = pdProvider.ProposerDuties(blockchain.Ctx, &api.ProposerDutiesOpts{
+			Epoch: epoch,
+		})
+		return err
+	})
+	return response, err
+}
+
+// attesterDuties obtains the attester duties of the validators in the epoch, retrying transient errors.
+func attesterDuties(epoch phase0.Epoch, indices []phase0.ValidatorIndex) (*api.Response[[]*apiv1.AttesterDuty], error) {
+	var response *api.Response[[]*apiv1.AttesterDuty]
+	err := retry(func() error {
+		var err error
+		response, err = attesterDutiesProvider.AttesterDuties(blockchain.Ctx, &api.AttesterDutiesOpts{
+			Epoch:   epoch,
+			Indices: indices,
+		})
+		return err
+	})
+	return response, err
+}
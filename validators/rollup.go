@@ -0,0 +1,139 @@
+package validators
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// validatorParticipation counts the attestation duties, faults and proposals of a validator across epochs.
+type validatorParticipation struct {
+	Duties          int
+	Missed          int
+	IncorrectHead   int
+	UntimelyHead    int
+	UntimelySource  int
+	IncorrectTarget int
+	UntimelyTarget  int
+	// TotalInclusionDistance is the sum of the inclusion distances of the validator's included attestations.
+	TotalInclusionDistance int
+	Proposals              int
+	MissedProposals        int
+	PubKey                 string
+}
+
+// validatorParticipations totals the participation of each validator in the epoch summaries, including validators
+// that had no duties in the range, and returns the validator indices in order.
+func validatorParticipations(summaries []*validatorSummary) ([]phase0.ValidatorIndex, map[phase0.ValidatorIndex]*validatorParticipation) {
+	participation := make(map[phase0.ValidatorIndex]*validatorParticipation)
+	get := func(index phase0.ValidatorIndex) *validatorParticipation {
+		if _, exists := participation[index]; !exists {
+			participation[index] = &validatorParticipation{}
+		}
+		return participation[index]
+	}
+	for _, summary := range summaries {
+		if summary.TextSummary == "" {
+			continue
+		}
+		for _, v := range summary.Validators {
+			get(v.Index).PubKey = pubKeyOf(summary.Validators, v.Index)
+		}
+		for _, v := range summary.AttestingValidators {
+			p := get(v.Validator.Index)
+			p.Duties++
+			p.TotalInclusionDistance += v.InclusionDistance
+		}
+		for _, v := range summary.NonParticipatingValidators {
+			p := get(v.Validator)
+			p.Duties++
+			p.Missed++
+		}
+		for _, v := range summary.IncorrectHeadValidators {
+			get(v.Validator).IncorrectHead++
+		}
+		for _, v := range summary.UntimelyHeadValidators {
+			get(v.Validator).UntimelyHead++
+		}
+		for _, v := range summary.UntimelySourceValidators {
+			get(v.Validator).UntimelySource++
+		}
+		for _, v := range summary.IncorrectTargetValidators {
+			get(v.Validator).IncorrectTarget++
+		}
+		for _, v := range summary.UntimelyTargetValidators {
+			get(v.Validator).UntimelyTarget++
+		}
+		for _, p := range summary.Proposals {
+			v := get(p.Proposer)
+			v.Proposals++
+			if !p.Block {
+				v.MissedProposals++
+			}
+		}
+	}
+
+	indices := make([]phase0.ValidatorIndex, 0, len(participation))
+	for index := range participation {
+		indices = append(indices, index)
+	}
+	sort.Slice(indices, func(i int, j int) bool {
+		return indices[i] < indices[j]
+	})
+	return indices, participation
+}
+
+// rollupRow formats the effectiveness of a validator across epochs. Accuracies and the average inclusion distance are
+// of the included attestations and are not available in first-slot-only mode.
+func rollupRow(p *validatorParticipation, firstSlotOnly bool) []string {
+	included := p.Duties - p.Missed
+	participation, head, target, distance := "-", "-", "-", "-"
+	if p.Duties > 0 {
+		participation = fmt.Sprintf("%.2f%%", 100*float64(included)/float64(p.Duties))
+	}
+	if included > 0 && !firstSlotOnly {
+		head = fmt.Sprintf("%.2f%%", 100*float64(included-p.IncorrectHead)/float64(included))
+		target = fmt.Sprintf("%.2f%%", 100*float64(included-p.IncorrectTarget)/float64(included))
+		distance = fmt.Sprintf("%.2f", float64(p.TotalInclusionDistance)/float64(included))
+	}
+	return []string{fmt.Sprintf("%d", p.Duties), fmt.Sprintf("%d", included), participation, head, target, distance, fmt.Sprintf("%d", p.Proposals), fmt.Sprintf("%d", p.MissedProposals)}
+}
+
+var rollupColumns = []string{"Expected", "Included", "Participation", "Head accuracy", "Target accuracy", "Avg inclusion distance", "Proposals", "Missed proposals"}
+
+// logValidatorRollup logs a table of the attestation effectiveness and missed proposals of each validator across all
+// the epoch summaries, to find the validators that are underperforming.
+func logValidatorRollup(summaries []*validatorSummary, firstSlotOnly bool) {
+	indices, participation := validatorParticipations(summaries)
+	if len(indices) == 0 {
+		return
+	}
+	builder := strings.Builder{}
+	w := tabwriter.NewWriter(&builder, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "VALIDATOR\t%s\n", strings.ToUpper(strings.Join(rollupColumns, "\t")))
+	for _, index := range indices {
+		p := participation[index]
+		fmt.Fprintf(w, "%s\t%s\n", validatorName(index, p.PubKey), strings.Join(rollupRow(p, firstSlotOnly), "\t"))
+	}
+	w.Flush()
+	log.Infof("Per-validator summary:\n%s", builder.String())
+}
+
+// markdownValidatorRollup renders the per-validator summary as a Markdown table.
+func markdownValidatorRollup(summaries []*validatorSummary, firstSlotOnly bool) string {
+	indices, participation := validatorParticipations(summaries)
+	if len(indices) == 0 {
+		return ""
+	}
+	builder := strings.Builder{}
+	builder.WriteString(fmt.Sprintf("| Validator | %s |\n", strings.Join(rollupColumns, " | ")))
+	builder.WriteString("|---|" + strings.Repeat("---:|", len(rollupColumns)) + "\n")
+	for _, index := range indices {
+		p := participation[index]
+		builder.WriteString(fmt.Sprintf("| %s | %s |\n", validatorName(index, p.PubKey), strings.Join(rollupRow(p, firstSlotOnly), " | ")))
+	}
+	return builder.String()
+}
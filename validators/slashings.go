@@ -0,0 +1,114 @@
+package validators
+
+import (
+	"fmt"
+	"net/http"
+
+	api "github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+
+	"github.com/allisterb/strac/blockchain"
+)
+
+type slashing struct {
+	Slot      phase0.Slot
+	Validator phase0.ValidatorIndex
+	Type      string
+}
+
+// Slashings scans the blocks in the epoch range for attester and proposer slashings and reports the slashings of any
+// of the validators, including the slot of the block the slashing was included in.
+func Slashings(validatorsStr []string, start string, end string, num string) error {
+	if len(validatorsStr) == 0 {
+		return fmt.Errorf("at least 1 validator index or public key must be specified to check for slashings")
+	}
+	if err := Init(); err != nil {
+		return err
+	}
+	startEpoch, endEpoch, err := parseEpochRange(start, end, num)
+	if err != nil {
+		return err
+	}
+	firstSlot, lastSlot, err := slotRange(startEpoch, endEpoch)
+	if err != nil {
+		return err
+	}
+	validators, err := parseValidators(blockchain.Ctx, validatorsStr, "head")
+	if err != nil {
+		return err
+	}
+	indices := make(map[phase0.ValidatorIndex]struct{})
+	for _, validator := range validators {
+		indices[validator.Index] = struct{}{}
+	}
+
+	log.Infof("Scanning slots %d to %d in epochs %v to %v for slashings...", firstSlot, lastSlot, startEpoch, endEpoch)
+	slashings, err := scanSlots(firstSlot, lastSlot, func(slot phase0.Slot) ([]*slashing, error) {
+		return slotSlashings(indices, slot)
+	})
+	if err != nil {
+		return err
+	}
+	slashed := make(map[phase0.ValidatorIndex]bool)
+	for _, s := range slashings {
+		slashed[s.Validator] = true
+		log.Warnf("Slot %d (epoch %d): validator %s was slashed by a %s slashing.", s.Slot, chainTime.SlotToEpoch(s.Slot), validatorName(s.Validator, pubKeyOf(validators, s.Validator)), s.Type)
+	}
+	for _, validator := range validators {
+		if validator.Validator.Slashed && !slashed[validator.Index] {
+			name := validatorName(validator.Index, hexutil.Encode(validator.Validator.PublicKey[:]))
+			log.Warnf("Validator %s has been slashed outside of epochs %v to %v.", name, startEpoch, endEpoch)
+		}
+	}
+	if len(slashings) == 0 {
+		log.Infof("None of the %d validator(s) were slashed in epochs %v to %v.", len(validators), startEpoch, endEpoch)
+	} else {
+		log.Infof("%d slashing(s) of %d validator(s) in epochs %v to %v.", len(slashings), len(slashed), startEpoch, endEpoch)
+	}
+	return nil
+}
+
+// slotSlashings returns the slashings of the validators in the block at the slot. A validator is slashed by an
+// attester slashing if it attested to both of the conflicting attestations.
+func slotSlashings(indices map[phase0.ValidatorIndex]struct{}, slot phase0.Slot) ([]*slashing, error) {
+	blockResponse, err := signedBeaconBlock(slot)
+	if err != nil {
+		var apiErr *api.Error
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			// No block so no slashings.
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to obtain block for slot %d", slot))
+	}
+	results := make([]*slashing, 0)
+	proposerSlashings, err := blockResponse.Data.ProposerSlashings()
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to obtain proposer slashings for slot %d", slot))
+	}
+	for _, s := range proposerSlashings {
+		if _, exists := indices[s.SignedHeader1.Message.ProposerIndex]; exists {
+			results = append(results, &slashing{Slot: slot, Validator: s.SignedHeader1.Message.ProposerIndex, Type: "proposer"})
+		}
+	}
+	attesterSlashings, err := blockResponse.Data.AttesterSlashings()
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to obtain attester slashings for slot %d", slot))
+	}
+	for _, s := range attesterSlashings {
+		attested := make(map[uint64]struct{})
+		for _, index := range s.Attestation1.AttestingIndices {
+			attested[index] = struct{}{}
+		}
+		for _, index := range s.Attestation2.AttestingIndices {
+			if _, exists := attested[index]; !exists {
+				continue
+			}
+			if _, exists := indices[phase0.ValidatorIndex(index)]; exists {
+				results = append(results, &slashing{Slot: slot, Validator: phase0.ValidatorIndex(index), Type: "attester"})
+			}
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,213 @@
+package validators
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+
+	api "github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+
+	"github.com/allisterb/strac/blockchain"
+	"github.com/allisterb/strac/util"
+)
+
+// Spec constants for the share of rewards of each duty.
+const (
+	timelySourceWeight = 14
+	timelyTargetWeight = 26
+	timelyHeadWeight   = 14
+	syncRewardWeight   = 2
+	proposerWeight     = 8
+	weightDenominator  = 64
+)
+
+type syncCommitteeContribution struct {
+	Index    phase0.ValidatorIndex `json:"index"`
+	Expected int                   `json:"expected"`
+	Included int                   `json:"included"`
+	Missed   int                   `json:"missed"`
+}
+
+// SyncCommitteeRewards reports, for each of the validators that were in the sync committee during the epoch range,
+// how many of their possible sync contributions were included in blocks and the estimated reward impact of those that were missed.
+func SyncCommitteeRewards(validatorsStr []string, start string, end string, num string) error {
+	if len(validatorsStr) == 0 {
+		return fmt.Errorf("at least 1 validator index or public key must be specified to retrieve sync committee contributions for")
+	}
+	if err := Init(); err != nil {
+		return err
+	}
+	startEpoch, endEpoch, err := parseEpochRange(start, end, num)
+	if err != nil {
+		return err
+	}
+	validators, err := parseValidators(blockchain.Ctx, validatorsStr, "head")
+	if err != nil {
+		return err
+	}
+	contributions := make(map[phase0.ValidatorIndex]*syncCommitteeContribution)
+	for _, validator := range validators {
+		contributions[validator.Index] = &syncCommitteeContribution{Index: validator.Index}
+	}
+
+	log.Infof("fetching sync committee contributions for start epoch: %v, end epoch: %v.", startEpoch, endEpoch)
+	for epoch := startEpoch; epoch <= endEpoch; epoch++ {
+		e := epoch
+		committeeResponse, err := syncCommitteesProvider.SyncCommittee(blockchain.Ctx, &api.SyncCommitteeOpts{
+			State: fmt.Sprintf("%d", chainTime.FirstSlotOfEpoch(epoch)),
+			Epoch: &e,
+		})
+		if err != nil {
+			return util.WrapError(err, "failed to obtain sync committee for epoch %d", epoch)
+		}
+		// A validator can occupy more than one position in the committee.
+		positions := make(map[phase0.ValidatorIndex][]uint64)
+		for i, index := range committeeResponse.Data.Validators {
+			if _, exists := contributions[index]; exists {
+				positions[index] = append(positions[index], uint64(i))
+			}
+		}
+		if len(positions) == 0 {
+			continue
+		}
+
+		for slot := chainTime.FirstSlotOfEpoch(epoch); slot <= chainTime.LastSlotOfEpoch(epoch) && slot <= chainTime.CurrentSlot(); slot++ {
+			blockResponse, err := signedBeaconBlock(slot)
+			if err != nil {
+				var apiErr *api.Error
+				if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+					// No block so no sync contributions could be included.
+					continue
+				}
+				return errors.Wrap(err, fmt.Sprintf("failed to obtain block for slot %d", slot))
+			}
+			aggregate, err := blockResponse.Data.SyncAggregate()
+			if err != nil {
+				return util.WrapError(err, "failed to obtain sync aggregate for slot %d", slot)
+			}
+			for index, committeePositions := range positions {
+				for _, position := range committeePositions {
+					contributions[index].Expected++
+					if aggregate.SyncCommitteeBits.BitAt(position) {
+						contributions[index].Included++
+					} else {
+						contributions[index].Missed++
+					}
+				}
+			}
+		}
+	}
+
+	participantReward, err := syncCommitteeParticipantReward()
+	if err != nil {
+		return err
+	}
+	results := make([]*syncCommitteeContribution, 0)
+	for _, c := range contributions {
+		if c.Expected > 0 {
+			results = append(results, c)
+		}
+	}
+	sort.Slice(results, func(i int, j int) bool {
+		return results[i].Index < results[j].Index
+	})
+	if len(results) == 0 {
+		log.Infof("None of the validator(s) were in the sync committee for epochs %v to %v.", startEpoch, endEpoch)
+		return nil
+	}
+	log.Infof("Estimated reward per included sync contribution: %v Gwei.", participantReward)
+	for _, c := range results {
+		// A missed contribution forfeits the participant reward and incurs a penalty of the same size.
+		lost := uint64(c.Missed) * 2 * participantReward
+		log.Infof("Validator %s: %d of %d sync contributions included, %d missed, estimated reward impact -%.9f %s.",
+			validatorName(c.Index, pubKeyOf(validators, c.Index)), c.Included, c.Expected, c.Missed, float64(lost)/1e9, util.NativeSymbol)
+	}
+	return nil
+}
+
+// rewardParameters holds the spec values and total active balance needed to estimate altair rewards.
+type rewardParameters struct {
+	Increment              uint64
+	BaseRewardPerIncrement uint64
+	TotalActiveBalance     uint64
+	ActiveValidators       uint64
+	SlotsPerEpoch          uint64
+	SyncCommitteeSize      uint64
+}
+
+// totalBaseRewards returns the sum of the base rewards in Gwei of all active validators in an epoch.
+func (p *rewardParameters) totalBaseRewards() uint64 {
+	return p.BaseRewardPerIncrement * (p.TotalActiveBalance / p.Increment)
+}
+
+// baseReward returns the base reward in Gwei of a validator with the given effective balance.
+func (p *rewardParameters) baseReward(effectiveBalance phase0.Gwei) uint64 {
+	return uint64(effectiveBalance) / p.Increment * p.BaseRewardPerIncrement
+}
+
+// currentRewardParameters obtains the reward parameters from the spec and the current total active balance.
+func currentRewardParameters() (*rewardParameters, error) {
+	specResponse, err := specProvider.Spec(blockchain.Ctx, &api.SpecOpts{})
+	if err != nil {
+		return nil, util.WrapError(err, "failed to obtain spec")
+	}
+	values := make(map[string]uint64)
+	for _, k := range []string{"EFFECTIVE_BALANCE_INCREMENT", "BASE_REWARD_FACTOR", "SYNC_COMMITTEE_SIZE", "SLOTS_PER_EPOCH"} {
+		v, ok := specResponse.Data[k].(uint64)
+		if !ok {
+			return nil, fmt.Errorf("%s not found in spec", k)
+		}
+		values[k] = v
+	}
+
+	response, err := validatorsProvider.Validators(blockchain.Ctx, &api.ValidatorsOpts{State: "head"})
+	if err != nil {
+		return nil, util.WrapError(err, "failed to obtain validator set")
+	}
+	epoch := chainTime.CurrentEpoch()
+	totalActiveBalance, activeValidators := uint64(0), uint64(0)
+	for _, validator := range response.Data {
+		if validator.Validator.ActivationEpoch <= epoch && validator.Validator.ExitEpoch > epoch {
+			totalActiveBalance += uint64(validator.Validator.EffectiveBalance)
+			activeValidators++
+		}
+	}
+	if totalActiveBalance == 0 {
+		return nil, fmt.Errorf("no active validators found")
+	}
+
+	increment := values["EFFECTIVE_BALANCE_INCREMENT"]
+	return &rewardParameters{
+		Increment:              increment,
+		BaseRewardPerIncrement: increment * values["BASE_REWARD_FACTOR"] / uint64(math.Sqrt(float64(totalActiveBalance))),
+		TotalActiveBalance:     totalActiveBalance,
+		ActiveValidators:       activeValidators,
+		SlotsPerEpoch:          values["SLOTS_PER_EPOCH"],
+		SyncCommitteeSize:      values["SYNC_COMMITTEE_SIZE"],
+	}, nil
+}
+
+// syncCommitteeParticipantReward estimates the reward in Gwei for a single included sync committee contribution using the
+// altair reward formula and the current total active balance.
+func syncCommitteeParticipantReward() (uint64, error) {
+	params, err := currentRewardParameters()
+	if err != nil {
+		return 0, err
+	}
+	maxParticipantRewards := params.totalBaseRewards() * syncRewardWeight / weightDenominator / params.SlotsPerEpoch
+	return maxParticipantRewards / params.SyncCommitteeSize, nil
+}
+
+func pubKeyOf(validators []*apiv1.Validator, index phase0.ValidatorIndex) string {
+	for _, validator := range validators {
+		if validator.Index == index {
+			return hexutil.Encode(validator.Validator.PublicKey[:])
+		}
+	}
+	return ""
+}
@@ -1,14 +1,23 @@
 package validators
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
+	"os"
+	"os/signal"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
 
 	eth2client "github.com/attestantio/go-eth2-client"
 	api "github.com/attestantio/go-eth2-client/api"
@@ -33,6 +42,8 @@ type attestingValidator struct {
 	Validator *apiv1.Validator      `json:"validator"`
 	Slot      phase0.Slot           `json:"slot"`
 	Committee phase0.CommitteeIndex `json:"committee_index"`
+	// InclusionDistance is not computed in first-slot-only mode.
+	InclusionDistance int `json:"inclusion_distance,omitempty"`
 }
 
 type nonParticipatingValidator struct {
@@ -54,12 +65,34 @@ type slotAttestations struct {
 	CorrectTarget int `json:"correct_target"`
 	TimelyTarget  int `json:"timely_target"`
 	TimelySource  int `json:"timely_source"`
+	// TotalInclusionDistance is the sum of the inclusion distances of the included attestations.
+	TotalInclusionDistance int `json:"total_inclusion_distance"`
+}
+
+// effectiveness is the headline attestation performance of the validators in an epoch. Accuracies and the average
+// inclusion distance are of the included attestations.
+type effectiveness struct {
+	Participation            float64 `json:"participation"`
+	HeadAccuracy             float64 `json:"head_accuracy"`
+	TargetAccuracy           float64 `json:"target_accuracy"`
+	AverageInclusionDistance float64 `json:"average_inclusion_distance"`
 }
 
 type epochProposal struct {
 	Slot     phase0.Slot           `json:"slot"`
 	Proposer phase0.ValidatorIndex `json:"proposer"`
 	Block    bool                  `json:"block"`
+	Graffiti string                `json:"graffiti,omitempty"`
+	// Attestations and SyncBits are the number of attestations and sync committee bits included in the block.
+	Attestations int `json:"attestations"`
+	SyncBits     int `json:"sync_bits"`
+	SyncSize     int `json:"sync_size"`
+	// AttestingBits is the total number of aggregation bits set in the attestations included in the block.
+	AttestingBits int `json:"attesting_bits"`
+	// Reward is the estimated proposer reward in Gwei of a produced block and LostReward the estimated reward of a
+	// missed proposal.
+	Reward     uint64 `json:"reward"`
+	LostReward uint64 `json:"lost_reward,omitempty"`
 }
 
 type epochSyncCommittee struct {
@@ -84,7 +117,16 @@ type validatorSummary struct {
 	Slots                      []*slot                      `json:"slots"`
 	Proposals                  []*epochProposal             `json:"-"`
 	SyncCommittee              []*epochSyncCommittee        `json:"-"`
-	TextSummary                string
+	BLSCredentialValidators    []phase0.ValidatorIndex      `json:"bls_credential_validators"`
+	Effectiveness              *effectiveness               `json:"effectiveness"`
+	TextSummary                string                       `json:"-"`
+}
+
+// validatorSummaryWithDuties serializes a validator summary together with its proposal and sync committee duties.
+type validatorSummaryWithDuties struct {
+	*validatorSummary
+	Proposals     []*epochProposal      `json:"proposals"`
+	SyncCommittee []*epochSyncCommittee `json:"sync_committee"`
 }
 
 var validatorsProvider eth2client.ValidatorsProvider
@@ -94,11 +136,20 @@ var pdProvider eth2client.ProposerDutiesProvider
 var blocksProvider eth2client.SignedBeaconBlockProvider
 var beaconBlockHeadersProvider eth2client.BeaconBlockHeadersProvider
 var attesterDutiesProvider eth2client.AttesterDutiesProvider
+var syncCommitteesProvider eth2client.SyncCommitteesProvider
+var finalityProvider eth2client.FinalityProvider
 var chainTime *chaintime.ChainTime
 
 var log = logging.Logger("strac/validators")
 
 func Init() error {
+	if chainTime != nil {
+		// Already initialized e.g. by an earlier command in the REPL.
+		return nil
+	}
+	if err := blockchain.RequireCC(); err != nil {
+		return err
+	}
 	isProvider := false
 	var err error
 
@@ -137,110 +188,450 @@ func Init() error {
 		return fmt.Errorf("could not get attester duties provider interface")
 	}
 
-	chainTime, err = chaintime.NewChainTime(chaintime.WithGenesisProvider(genesisProvider), chaintime.WithSpecProvider(specProvider))
+	syncCommitteesProvider, isProvider = blockchain.BeaconClient.(eth2client.SyncCommitteesProvider)
+	if !isProvider {
+		return fmt.Errorf("could not get sync committees provider interface")
+	}
+
+	finalityProvider, isProvider = blockchain.BeaconClient.(eth2client.FinalityProvider)
+	if !isProvider {
+		return fmt.Errorf("could not get finality provider interface")
+	}
+
+	chainTime, err = chaintime.NewChainTime(chaintime.WithGenesisProvider(genesisProvider), chaintime.WithSpecProvider(specProvider), chaintime.WithCacheFile(chaintime.DefaultCacheFile()))
 	if err != nil {
 		return util.WrapError(err, "could not get chain time")
 	}
 
 	return nil
 }
-func Perf(validators []string, stateID string, start string, end string, num string) error {
-	var err error
-	var startEpoch phase0.Epoch
-	var endEpoch phase0.Epoch
-	var numEpochs uint64
+func Perf(validators []string, stateID string, start string, end string, num string, missedBlocks bool, firstSlotOnly bool, allowUnfinalized bool, summaryOnly bool, markdown bool, includeDuties bool, csvFile string) error {
+	if len(validators) == 0 {
+		return fmt.Errorf("at least 1 validator index or public key must be specified to retrieve validator info for")
+	}
+	if markdown && util.Output != "text" {
+		return fmt.Errorf("can't specify both markdown and %s output", util.Output)
+	}
+
+	if err := Init(); err != nil {
+		return err
+	}
+
+	startEpoch, endEpoch, err := parseEpochRange(start, end, num)
+	if err != nil {
+		return err
+	}
+	if !allowUnfinalized {
+		finalized, err := finalizedEpoch()
+		if err != nil {
+			return err
+		}
+		// Unfinalized epochs give incomplete and misleading results so move the range back to end at the last finalized epoch.
+		if endEpoch > finalized {
+			shift := endEpoch - finalized
+			if shift > startEpoch {
+				shift = startEpoch
+			}
+			log.Infof("Note: epoch %v is not finalized yet so analyzing epochs %v to %v instead. Use --allow-unfinalized to analyze unfinalized epochs.", endEpoch, startEpoch-shift, finalized)
+			startEpoch, endEpoch = startEpoch-shift, finalized
+		}
+	}
+
+	if current := chainTime.CurrentEpoch(); endEpoch > current {
+		if startEpoch > current {
+			return fmt.Errorf("the start epoch %v is beyond the current epoch %v", startEpoch, current)
+		}
+		log.Warnf("Skipping epochs %v to %v which are beyond the current epoch %v.", current+1, endEpoch, current)
+		endEpoch = current
+	}
+
+	log.Infof("fetching validator(s) performance data for start epoch: %v, end epoch: %v.", startEpoch, endEpoch)
+	if firstSlotOnly {
+		log.Warnf("first-slot-only mode enabled: only attestation participation will be checked, head and target correctness and inclusion distance will not be computed.")
+	}
+
+	results := epochSummaries(validators, stateID, startEpoch, endEpoch, firstSlotOnly)
+	summaries := make([]any, 0, len(results))
+	for _, r := range results {
+		if r.TextSummary == "" {
+			continue
+		}
+		if includeDuties {
+			summaries = append(summaries, &validatorSummaryWithDuties{validatorSummary: r, Proposals: r.Proposals, SyncCommittee: r.SyncCommittee})
+		} else {
+			summaries = append(summaries, r)
+		}
+	}
+	err = util.Render(summaries, func() {
+		if markdown {
+			fmt.Print(markdownSummary(results))
+			if rollup := markdownValidatorRollup(results, firstSlotOnly); rollup != "" {
+				fmt.Print("\n" + rollup)
+			}
+		} else if summaryOnly {
+			logRollup(results)
+			logValidatorRollup(results, firstSlotOnly)
+		} else {
+			for i := range results {
+				if results[i].TextSummary == "" {
+					continue
+				}
+				log.Infof(results[i].TextSummary)
+			}
+			if endEpoch > startEpoch {
+				logRollup(results)
+				logValidatorRollup(results, firstSlotOnly)
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if csvFile != "" {
+		if err := writePerfCSV(results, csvFile); err != nil {
+			return err
+		}
+	}
+
+	if missedBlocks {
+		slots, err := missedProposals(startEpoch, endEpoch)
+		if err != nil {
+			return err
+		}
+		log.Infof("Missed proposals network-wide for epochs %v to %v: %d", startEpoch, endEpoch, len(slots))
+		for _, s := range slots {
+			log.Infof("  Slot %d (epoch %d) at %v", s, chainTime.SlotToEpoch(s), chainTime.StartOfSlot(s))
+		}
+	}
 
+	return nil
+}
+
+// Timeseries writes a CSV file with one row per epoch of the aggregate participation and missed proposals of the validators.
+func Timeseries(validators []string, stateID string, start string, end string, num string, out string) error {
 	if len(validators) == 0 {
 		return fmt.Errorf("at least 1 validator index or public key must be specified to retrieve validator info for")
 	}
-	if start != "" && end != "" && num != "" {
-		return fmt.Errorf("can't specify all 3 of start and end and num-epochs")
+	if err := Init(); err != nil {
+		return err
+	}
+	startEpoch, endEpoch, err := parseEpochRange(start, end, num)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("fetching validator(s) time series data for start epoch: %v, end epoch: %v.", startEpoch, endEpoch)
+	results := epochSummaries(validators, stateID, startEpoch, endEpoch, false)
+
+	f, err := os.Create(out)
+	if err != nil {
+		return util.WrapError(err, "could not create file %s", out)
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	if err := w.Write([]string{"epoch", "timestamp", "active", "participating", "participation_rate", "missed_proposals"}); err != nil {
+		return err
+	}
+	rows := 0
+	for _, summary := range results {
+		if summary.TextSummary == "" {
+			continue
+		}
+		rate := 0.0
+		if summary.ActiveValidators > 0 {
+			rate = float64(summary.ParticipatingValidators) / float64(summary.ActiveValidators)
+		}
+		missed := 0
+		for _, p := range summary.Proposals {
+			if !p.Block {
+				missed++
+			}
+		}
+		if err := w.Write([]string{
+			fmt.Sprintf("%d", summary.Epoch),
+			chainTime.StartOfEpoch(summary.Epoch).UTC().Format(time.RFC3339),
+			fmt.Sprintf("%d", summary.ActiveValidators),
+			fmt.Sprintf("%d", summary.ParticipatingValidators),
+			fmt.Sprintf("%.4f", rate),
+			fmt.Sprintf("%d", missed),
+		}); err != nil {
+			return err
+		}
+		rows++
 	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return util.WrapError(err, "could not write file %s", out)
+	}
+	if err := f.Close(); err != nil {
+		return util.WrapError(err, "could not write file %s", out)
+	}
+	log.Infof("Wrote %d epoch(s) to %s.", rows, out)
+	return nil
+}
 
-	if err = Init(); err != nil {
+// BalanceHistory reports the balance of each of the validators at the start of each epoch in the range, the change
+// from the previous epoch and the net change over the range. Validators that are not yet activated at an epoch are
+// shown as inactive.
+func BalanceHistory(validatorsStr []string, start string, end string, num string) error {
+	if len(validatorsStr) == 0 {
+		return fmt.Errorf("at least 1 validator index or public key must be specified to retrieve the balance history of")
+	}
+	if err := Init(); err != nil {
+		return err
+	}
+	startEpoch, endEpoch, err := parseEpochRange(start, end, num)
+	if err != nil {
+		return err
+	}
+	if current := chainTime.CurrentEpoch(); endEpoch > current {
+		if startEpoch > current {
+			return fmt.Errorf("the start epoch %v is beyond the current epoch %v", startEpoch, current)
+		}
+		log.Warnf("Skipping epochs %v to %v which are beyond the current epoch %v.", current+1, endEpoch, current)
+		endEpoch = current
+	}
+
+	log.Infof("fetching validator(s) balances for start epoch: %v, end epoch: %v.", startEpoch, endEpoch)
+	first := make(map[phase0.ValidatorIndex]phase0.Gwei)
+	last := make(map[phase0.ValidatorIndex]phase0.Gwei)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "EPOCH\tVALIDATOR\tBALANCE\tCHANGE")
+	for epoch := startEpoch; epoch <= endEpoch; epoch++ {
+		validators, err := parseValidators(blockchain.Ctx, validatorsStr, fmt.Sprintf("%d", chainTime.FirstSlotOfEpoch(epoch)))
+		if err != nil {
+			return util.WrapError(err, "failed to obtain validator balances for epoch %d", epoch)
+		}
+		sort.Slice(validators, func(i int, j int) bool {
+			return validators[i].Index < validators[j].Index
+		})
+		for _, validator := range validators {
+			if !validator.Status.HasActivated() {
+				fmt.Fprintf(w, "%d\t%d\tinactive (%v)\t-\n", epoch, validator.Index, validator.Status)
+				continue
+			}
+			change := "-"
+			if previous, exists := last[validator.Index]; exists {
+				change = signedGweiString(int64(validator.Balance) - int64(previous))
+			} else {
+				first[validator.Index] = validator.Balance
+			}
+			last[validator.Index] = validator.Balance
+			fmt.Fprintf(w, "%d\t%d\t%s\t%s\n", epoch, validator.Index, gweiString(uint64(validator.Balance)), change)
+		}
+	}
+	if err := w.Flush(); err != nil {
 		return err
 	}
+	if len(last) == 0 {
+		log.Infof("None of the validator(s) were active in epochs %v to %v.", startEpoch, endEpoch)
+		return nil
+	}
+	indices := make([]phase0.ValidatorIndex, 0, len(last))
+	for index := range last {
+		indices = append(indices, index)
+	}
+	sort.Slice(indices, func(i int, j int) bool {
+		return indices[i] < indices[j]
+	})
+	for _, index := range indices {
+		log.Infof("Validator %d: net change over epochs %v to %v: %s.", index, startEpoch, endEpoch, signedGweiString(int64(last[index])-int64(first[index])))
+	}
+	return nil
+}
+
+// epochSummaries concurrently computes the summary of each epoch in the range. Epochs that could not be
+// processed are logged and left as empty summaries.
+func epochSummaries(validators []string, stateID string, startEpoch phase0.Epoch, endEpoch phase0.Epoch, firstSlotOnly bool) []*validatorSummary {
+	n := int(endEpoch-startEpoch) + 1
+	wg := new(sync.WaitGroup)
+	wg.Add(n)
+	results := make([]*validatorSummary, n)
+	for i := 0; i < n; i++ {
+		results[i] = &validatorSummary{}
+		e := strconv.FormatUint(uint64(startEpoch+phase0.Epoch(i)), 10)
+		go func(index int) {
+			util.Concurrency.Acquire()
+			defer util.Concurrency.Release()
+			s, err := EpochSummary(validators, stateID, e, firstSlotOnly)
+			if err != nil {
+				log.Errorf("Error retrieving validator info for epoch %s: %v", e, err)
+			} else {
+				results[index] = s
+			}
+			wg.Done()
+
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+// logRollup logs the aggregate of the epoch summaries across the whole run without any per-validator or per-slot detail.
+func logRollup(summaries []*validatorSummary) {
+	epochs, active, participating, missedProposals := 0, 0, 0, 0
+	slashed := make(map[phase0.ValidatorIndex]struct{})
+	for _, summary := range summaries {
+		if summary.TextSummary == "" {
+			continue
+		}
+		epochs++
+		active += summary.ActiveValidators
+		participating += summary.ParticipatingValidators
+		for _, p := range summary.Proposals {
+			if !p.Block {
+				missedProposals++
+			}
+		}
+		for _, validator := range summary.Validators {
+			if validator.Validator.Slashed {
+				slashed[validator.Index] = struct{}{}
+			}
+		}
+	}
+	rate := 0.0
+	if active > 0 {
+		rate = 100 * float64(participating) / float64(active)
+	}
+	log.Infof("Summary of %d epoch(s): total active: %d, total participating: %d, participation rate: %.2f%%, missed proposals: %d, slashed validators: %d.",
+		epochs, active, participating, rate, missedProposals, len(slashed))
+}
+
+// parseEpochRange parses the start, end and number of epochs arguments to obtain the epoch range to process.
+func parseEpochRange(start string, end string, num string) (phase0.Epoch, phase0.Epoch, error) {
+	var err error
+	var startEpoch phase0.Epoch
+	var endEpoch phase0.Epoch
+	var numEpochs uint64
+
+	if start != "" && end != "" && num != "" {
+		return 0, 0, fmt.Errorf("can't specify all 3 of start and end and num-epochs")
+	}
 
 	if start == "" && end == "" && num == "" {
 		startEpoch = chainTime.CurrentEpoch()
 		endEpoch = startEpoch
 	} else if start != "" && end == "" && num == "" {
 		if startEpoch, err = chaintime.ParseEpoch(chainTime, start); err != nil {
-			return err
+			return 0, 0, err
 		}
 		endEpoch = startEpoch
 	} else if end != "" && start == "" && num == "" {
 		if endEpoch, err = chaintime.ParseEpoch(chainTime, end); err != nil {
-			return err
+			return 0, 0, err
 		}
 		startEpoch = endEpoch
 	} else if start != "" && num != "" {
 		if startEpoch, err = chaintime.ParseEpoch(chainTime, start); err != nil {
-			return err
+			return 0, 0, err
 		}
 		if numEpochs, err = strconv.ParseUint(num, 10, 0); err != nil {
-			return err
+			return 0, 0, err
 		}
 		endEpoch = startEpoch + phase0.Epoch(numEpochs)
 	} else if end != "" && num != "" {
 		if endEpoch, err = chaintime.ParseEpoch(chainTime, end); err != nil {
-			return err
+			return 0, 0, err
 		}
 		if numEpochs, err = strconv.ParseUint(num, 10, 0); err != nil {
-			return err
+			return 0, 0, err
 		}
 		startEpoch = endEpoch - phase0.Epoch(numEpochs)
 	} else if start != "" && end != "" {
 		if startEpoch, err = chaintime.ParseEpoch(chainTime, start); err != nil {
-			return err
+			return 0, 0, err
 		}
 		if endEpoch, err = chaintime.ParseEpoch(chainTime, end); err != nil {
-			return err
+			return 0, 0, err
 		}
 	} else if start == "" && end == "" && num != "" {
 		endEpoch = chainTime.CurrentEpoch()
 		if numEpochs, err = strconv.ParseUint(num, 10, 0); err != nil {
-			return err
+			return 0, 0, err
 		}
 		startEpoch = endEpoch - phase0.Epoch(numEpochs)
 	}
 
 	if startEpoch > endEpoch {
-		return fmt.Errorf("the start epoch specified: %v is greater than the end epoch specifed: %v", startEpoch, endEpoch)
+		return 0, 0, fmt.Errorf("the start epoch specified: %v is greater than the end epoch specifed: %v", startEpoch, endEpoch)
 	}
+	return startEpoch, endEpoch, nil
+}
 
-	log.Infof("fetching validator(s) performance data for start epoch: %v, end epoch: %v.", startEpoch, endEpoch)
+// slotRange returns the first and last slot of the epoch range, ending at the current slot if the range extends
+// beyond it.
+func slotRange(startEpoch phase0.Epoch, endEpoch phase0.Epoch) (phase0.Slot, phase0.Slot, error) {
+	current := chainTime.CurrentSlot()
+	firstSlot, lastSlot := chainTime.FirstSlotOfEpoch(startEpoch), chainTime.LastSlotOfEpoch(endEpoch)
+	if firstSlot > current {
+		return 0, 0, fmt.Errorf("the start epoch %v is beyond the current epoch %v", startEpoch, chainTime.CurrentEpoch())
+	}
+	if lastSlot > current {
+		lastSlot = current
+	}
+	return firstSlot, lastSlot, nil
+}
 
-	n := int(endEpoch-startEpoch) + 1
+// scanSlots concurrently calls scan for each slot in the range, bounded by util.Concurrency, and returns the results
+// of all the slots in slot order, or the error of the first slot that failed.
+func scanSlots[T any](firstSlot phase0.Slot, lastSlot phase0.Slot, scan func(slot phase0.Slot) ([]T, error)) ([]T, error) {
+	n := int(lastSlot-firstSlot) + 1
+	results := make([][]T, n)
+	errs := make([]error, n)
 	wg := new(sync.WaitGroup)
 	wg.Add(n)
-	results := make([]*validatorSummary, n)
 	for i := 0; i < n; i++ {
-		results[i] = &validatorSummary{}
-		e := strconv.FormatUint(uint64(startEpoch+phase0.Epoch(i)), 10)
 		go func(index int) {
-			s, err := EpochSummary(validators, stateID, e)
-			if err != nil {
-				log.Errorf("Error retrieving validator info for epoch %s: %v", e, err)
-			} else {
-				results[index] = s
-			}
-			wg.Done()
-
+			defer wg.Done()
+			util.Concurrency.Acquire()
+			defer util.Concurrency.Release()
+			results[index], errs[index] = scan(firstSlot + phase0.Slot(index))
 		}(i)
 	}
 	wg.Wait()
-	for i := 0; i < n; i++ {
-		if results[i].TextSummary == "" {
-			continue
+
+	all := make([]T, 0)
+	for i := range results {
+		if errs[i] != nil {
+			return nil, errs[i]
 		}
-		log.Infof(results[i].TextSummary)
+		all = append(all, results[i]...)
 	}
+	return all, nil
+}
 
-	return nil
+// finalizedEpoch returns the epoch of the most recent finalized checkpoint.
+func finalizedEpoch() (phase0.Epoch, error) {
+	response, err := finalityProvider.Finality(blockchain.Ctx, &api.FinalityOpts{State: "head"})
+	if err != nil {
+		return 0, util.WrapError(err, "failed to obtain finality")
+	}
+	return response.Data.Finalized.Epoch, nil
+}
+
+// missedProposals returns the slots in the epoch range for which no block was proposed by any validator.
+func missedProposals(startEpoch phase0.Epoch, endEpoch phase0.Epoch) ([]phase0.Slot, error) {
+	slots := make([]phase0.Slot, 0)
+	lastSlot := chainTime.LastSlotOfEpoch(endEpoch)
+	if lastSlot > chainTime.CurrentSlot() {
+		lastSlot = chainTime.CurrentSlot()
+	}
+	for slot := chainTime.FirstSlotOfEpoch(startEpoch); slot <= lastSlot; slot++ {
+		_, err := signedBeaconBlock(slot)
+		if err != nil {
+			var apiErr *api.Error
+			if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+				slots = append(slots, slot)
+				continue
+			}
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to obtain block for slot %d", slot))
+		}
+	}
+	return slots, nil
 }
 
-func EpochSummary(validatorsStr []string, stateID string, epoch string) (*validatorSummary, error) {
+func EpochSummary(validatorsStr []string, stateID string, epoch string, firstSlotOnly bool) (*validatorSummary, error) {
 	var err error
 	log.Infof("fetching validator(s) data for epoch %s...", epoch)
 	summary := &validatorSummary{}
@@ -274,55 +665,77 @@ func EpochSummary(validatorsStr []string, stateID string, epoch string) (*valida
 	if err := processProposerDuties(validatorsByIndex, summary); err != nil {
 		return nil, err
 	}
+	estimateProposalRewards(summary)
 
-	if err = processAttesterDuties(validatorsByIndex, summary); err != nil {
+	if err = processAttesterDuties(validatorsByIndex, summary, firstSlotOnly); err != nil {
 		return nil, err
 	}
 
+	summary.Effectiveness = epochEffectiveness(summary)
+
+	name := func(index phase0.ValidatorIndex) string {
+		if validator, exists := validatorsByIndex[index]; exists {
+			return validatorName(index, hexutil.Encode(validator.Validator.PublicKey[:]))
+		}
+		return validatorName(index, "")
+	}
+
 	builder := strings.Builder{}
 
 	builder.WriteString(fmt.Sprintf("Epoch %d:\n", summary.Epoch))
+	if firstSlotOnly {
+		builder.WriteString("  (first-slot-only mode: participation only, head/target/source correctness not checked)\n")
+	}
+	if e := summary.Effectiveness; e != nil && firstSlotOnly {
+		builder.WriteString(fmt.Sprintf("  Effectiveness: participation %.2f%%\n", e.Participation))
+	} else if e != nil {
+		builder.WriteString(fmt.Sprintf("  Effectiveness: participation %.2f%%, head accuracy %.2f%%, target accuracy %.2f%%, average inclusion distance %.2f\n",
+			e.Participation, e.HeadAccuracy, e.TargetAccuracy, e.AverageInclusionDistance))
+	}
 	if len(summary.Proposals) > 0 {
 		builder.WriteString("  Proposer validators: \n")
 		for _, p := range summary.Proposals {
-			validator := validatorsByIndex[p.Proposer]
-			builder.WriteString(fmt.Sprintf("    %v\n", validator.Index))
+			if p.Block {
+				builder.WriteString(fmt.Sprintf("    %s (slot %d, graffiti %q, est. reward %s)\n", name(p.Proposer), p.Slot, p.Graffiti, gweiString(p.Reward)))
+			} else {
+				builder.WriteString(fmt.Sprintf("    %s (slot %d, missed, reward 0, est. lost reward %s)\n", name(p.Proposer), p.Slot, gweiString(p.LostReward)))
+			}
 		}
 	}
 	if len(summary.NonParticipatingValidators) > 0 {
 		builder.WriteString("  Non-participating validators:\n")
 		for _, validator := range summary.NonParticipatingValidators {
-			builder.WriteString(fmt.Sprintf("    %d (slot %d, committee %d)\n", validator.Validator, validator.Slot, validator.Committee))
+			builder.WriteString(fmt.Sprintf("    %s (slot %d, committee %d)\n", name(validator.Validator), validator.Slot, validator.Committee))
 		}
 	}
 	if len(summary.IncorrectHeadValidators) > 0 {
 		builder.WriteString("  Incorrect head validators:\n")
 		for _, validator := range summary.IncorrectHeadValidators {
-			builder.WriteString(fmt.Sprintf("    %d (slot %d, committee %d)\n", validator.Validator, validator.AttestationData.Slot, validator.AttestationData.Index))
+			builder.WriteString(fmt.Sprintf("    %s (slot %d, committee %d)\n", name(validator.Validator), validator.AttestationData.Slot, validator.AttestationData.Index))
 		}
 	}
 	if len(summary.UntimelyHeadValidators) > 0 {
 		builder.WriteString("  Untimely head validators:\n")
 		for _, validator := range summary.UntimelyHeadValidators {
-			builder.WriteString(fmt.Sprintf("    %d (slot %d, committee %d, inclusion distance %d)\n", validator.Validator, validator.AttestationData.Slot, validator.AttestationData.Index, validator.InclusionDistance))
+			builder.WriteString(fmt.Sprintf("    %s (slot %d, committee %d, inclusion distance %d)\n", name(validator.Validator), validator.AttestationData.Slot, validator.AttestationData.Index, validator.InclusionDistance))
 		}
 	}
 	if len(summary.UntimelySourceValidators) > 0 {
 		builder.WriteString("  Untimely source validators:\n")
 		for _, validator := range summary.UntimelySourceValidators {
-			builder.WriteString(fmt.Sprintf("    %d (slot %d, committee %d, inclusion distance %d)\n", validator.Validator, validator.AttestationData.Slot, validator.AttestationData.Index, validator.InclusionDistance))
+			builder.WriteString(fmt.Sprintf("    %s (slot %d, committee %d, inclusion distance %d)\n", name(validator.Validator), validator.AttestationData.Slot, validator.AttestationData.Index, validator.InclusionDistance))
 		}
 	}
 	if len(summary.IncorrectTargetValidators) > 0 {
 		builder.WriteString("  Incorrect target validators:\n")
 		for _, validator := range summary.IncorrectTargetValidators {
-			builder.WriteString(fmt.Sprintf("    %d (slot %d, committee %d)\n", validator.Validator, validator.AttestationData.Slot, validator.AttestationData.Index))
+			builder.WriteString(fmt.Sprintf("    %s (slot %d, committee %d)\n", name(validator.Validator), validator.AttestationData.Slot, validator.AttestationData.Index))
 		}
 	}
 	if len(summary.UntimelyTargetValidators) > 0 {
 		builder.WriteString("  Untimely target validators:\n")
 		for _, validator := range summary.UntimelyTargetValidators {
-			builder.WriteString(fmt.Sprintf("    %d (slot %d, committee %d, inclusion distance %d)\n", validator.Validator, validator.AttestationData.Slot, validator.AttestationData.Index, validator.InclusionDistance))
+			builder.WriteString(fmt.Sprintf("    %s (slot %d, committee %d, inclusion distance %d)\n", name(validator.Validator), validator.AttestationData.Slot, validator.AttestationData.Index, validator.InclusionDistance))
 		}
 	}
 	if len(summary.NonParticipatingValidators) == 0 && len(summary.IncorrectHeadValidators) == 0 && len(summary.UntimelyHeadValidators) == 0 &&
@@ -330,7 +743,16 @@ func EpochSummary(validatorsStr []string, stateID string, epoch string) (*valida
 		len(summary.AttestingValidators) > 0 {
 		builder.WriteString("  Attesting validators: ")
 		for _, validator := range summary.AttestingValidators {
-			builder.WriteString(fmt.Sprintf("    %v\n", validator.Validator.Index))
+			builder.WriteString(fmt.Sprintf("    %s\n", name(validator.Validator.Index)))
+		}
+	}
+	for _, validator := range blsCredentialValidators(summary.Validators) {
+		summary.BLSCredentialValidators = append(summary.BLSCredentialValidators, validator.Index)
+	}
+	if len(summary.BLSCredentialValidators) > 0 {
+		builder.WriteString("  Validators with BLS (0x00) withdrawal credentials (needs withdrawal credential update):\n")
+		for _, index := range summary.BLSCredentialValidators {
+			builder.WriteString(fmt.Sprintf("    %s\n", name(index)))
 		}
 	}
 
@@ -339,57 +761,573 @@ func EpochSummary(validatorsStr []string, stateID string, epoch string) (*valida
 	return summary, nil
 }
 
-func Info(validatorPubKey string) error {
-	if provider, isProvider := blockchain.BeaconClient.(eth2client.ValidatorsProvider); isProvider {
-		pkey, _ := hexutil.Decode(validatorPubKey)
-		k := phase0.BLSPubKey{}
-		x := copy(k[:], pkey[:])
-		if x != 48 {
-			return fmt.Errorf("bad copy of validator public key")
+// epochEffectiveness computes the effectiveness of the validators from the attestations of each slot of the epoch,
+// or returns nil if the validators had no attestation duties.
+func epochEffectiveness(summary *validatorSummary) *effectiveness {
+	var expected, included, correctHead, correctTarget, inclusionDistance int
+	for _, s := range summary.Slots {
+		if s.Attestations == nil {
+			continue
+		}
+		expected += s.Attestations.Expected
+		included += s.Attestations.Included
+		correctHead += s.Attestations.CorrectHead
+		correctTarget += s.Attestations.CorrectTarget
+		inclusionDistance += s.Attestations.TotalInclusionDistance
+	}
+	if expected == 0 {
+		return nil
+	}
+	e := &effectiveness{Participation: 100 * float64(included) / float64(expected)}
+	if included > 0 {
+		e.HeadAccuracy = 100 * float64(correctHead) / float64(included)
+		e.TargetAccuracy = 100 * float64(correctTarget) / float64(included)
+		e.AverageInclusionDistance = float64(inclusionDistance) / float64(included)
+	}
+	return e
+}
+
+type validatorInfo struct {
+	Index                      phase0.ValidatorIndex `json:"index"`
+	Label                      string                `json:"label,omitempty"`
+	PubKey                     string                `json:"pubkey"`
+	Status                     string                `json:"status"`
+	ActivationEligibilityEpoch phase0.Epoch          `json:"activation_eligibility_epoch"`
+	ActivationEpoch            phase0.Epoch          `json:"activation_epoch"`
+	ExitEpoch                  phase0.Epoch          `json:"exit_epoch"`
+	WithdrawableEpoch          phase0.Epoch          `json:"withdrawable_epoch"`
+	// The times are the approximate starts of the lifecycle epochs, omitted for epochs that are not set yet.
+	ActivationEligibilityTime *time.Time  `json:"activation_eligibility_time,omitempty"`
+	ActivationTime            *time.Time  `json:"activation_time,omitempty"`
+	ExitTime                  *time.Time  `json:"exit_time,omitempty"`
+	WithdrawableTime          *time.Time  `json:"withdrawable_time,omitempty"`
+	Balance                   phase0.Gwei `json:"balance"`
+	EffectiveBalance          phase0.Gwei `json:"effective_balance"`
+	WithdrawalCredentials     string      `json:"withdrawal_credentials"`
+}
+
+// farFutureEpoch is the FAR_FUTURE_EPOCH value of the lifecycle epochs of a validator that haven't been set yet.
+const farFutureEpoch = phase0.Epoch(math.MaxUint64)
+
+type validatorStatusGroup struct {
+	Status     string           `json:"status"`
+	Count      int              `json:"count"`
+	Validators []*validatorInfo `json:"validators"`
+}
+
+func Info(validatorPubKeys []string, groupBy string) error {
+	if len(validatorPubKeys) == 0 {
+		return fmt.Errorf("at least 1 validator public key must be specified to retrieve validator info for")
+	}
+	if groupBy != "" && groupBy != "status" {
+		return fmt.Errorf("unknown group-by option %s, the only supported option is status", groupBy)
+	}
+	if err := Init(); err != nil {
+		return err
+	}
+	provider, isProvider := blockchain.BeaconClient.(eth2client.ValidatorsProvider)
+	if !isProvider {
+		return fmt.Errorf("could not get validator interface")
+	}
+	keys := make([]phase0.BLSPubKey, 0, len(validatorPubKeys))
+	for _, validatorPubKey := range validatorPubKeys {
+		k, err := util.ToPubKey(validatorPubKey)
+		if err != nil {
+			return util.WrapError(err, "bad validator public key %s", validatorPubKey)
+		}
+		keys = append(keys, k)
+	}
+	response, err := provider.Validators(blockchain.Ctx, &api.ValidatorsOpts{PubKeys: keys, State: "head"})
+	if err != nil {
+		return err
+	} else if len(response.Data) == 0 {
+		return fmt.Errorf("could not retrieve info on validator(s) with public key(s) %v", validatorPubKeys)
+	}
+
+	infos := make([]*validatorInfo, 0, len(response.Data))
+	states := make(map[string]apiv1.ValidatorState)
+	for _, v := range response.Data {
+		infos = append(infos, newValidatorInfo(v))
+		states[v.Status.String()] = v.Status
+	}
+	sort.Slice(infos, func(i int, j int) bool {
+		return infos[i].Index < infos[j].Index
+	})
+
+	if groupBy == "" {
+		return util.Render(infos, func() {
+			for _, v := range infos {
+				logValidatorInfo(v)
+			}
+		})
+	}
+
+	// Group validators by status in lifecycle order.
+	groups := make([]*validatorStatusGroup, 0)
+	groupsByStatus := make(map[string]*validatorStatusGroup)
+	for _, v := range infos {
+		group, exists := groupsByStatus[v.Status]
+		if !exists {
+			group = &validatorStatusGroup{Status: v.Status}
+			groupsByStatus[v.Status] = group
+			groups = append(groups, group)
+		}
+		group.Validators = append(group.Validators, v)
+		group.Count++
+	}
+	sort.Slice(groups, func(i int, j int) bool {
+		return states[groups[i].Status] < states[groups[j].Status]
+	})
+	return util.Render(groups, func() {
+		for _, group := range groups {
+			log.Infof("Validators with status %s: %d", group.Status, group.Count)
+			for _, v := range group.Validators {
+				logValidatorInfo(v)
+			}
+		}
+	})
+}
+
+// DepositDataRoot computes the SSZ hash tree root of the deposit data for a validator so it can be cross-checked against a deposit tool's output.
+func DepositDataRoot(pubKeyStr string, withdrawalCredentialsStr string, amount uint64, signatureStr string) error {
+	pubKey, err := hexutil.Decode(pubKeyStr)
+	if err != nil || len(pubKey) != 48 {
+		return fmt.Errorf("the validator public key must be 48 bytes of hex beginning with 0x")
+	}
+	withdrawalCredentials, err := hexutil.Decode(withdrawalCredentialsStr)
+	if err != nil || len(withdrawalCredentials) != 32 {
+		return fmt.Errorf("the withdrawal credentials must be 32 bytes of hex beginning with 0x")
+	}
+	signature, err := hexutil.Decode(signatureStr)
+	if err != nil || len(signature) != 96 {
+		return fmt.Errorf("the signature must be 96 bytes of hex beginning with 0x")
+	}
+	if amount == 0 {
+		return fmt.Errorf("the deposit amount must be greater than 0")
+	}
+	depositData := &phase0.DepositData{
+		WithdrawalCredentials: withdrawalCredentials,
+		Amount:                phase0.Gwei(amount),
+	}
+	copy(depositData.PublicKey[:], pubKey)
+	copy(depositData.Signature[:], signature)
+	root, err := depositData.HashTreeRoot()
+	if err != nil {
+		return util.WrapError(err, "could not compute deposit data root")
+	}
+	log.Infof("Deposit data root: %v", hexutil.Encode(root[:]))
+	return nil
+}
+
+// CheckCredentials warns about validators still using BLS (0x00) withdrawal credentials, which can't receive automatic withdrawals.
+func CheckCredentials(validatorsStr []string) error {
+	if len(validatorsStr) == 0 {
+		return fmt.Errorf("at least 1 validator index or public key must be specified to check withdrawal credentials for")
+	}
+	if err := Init(); err != nil {
+		return err
+	}
+	validators, err := parseValidators(blockchain.Ctx, validatorsStr, "head")
+	if err != nil {
+		return err
+	}
+	bls := blsCredentialValidators(validators)
+	for _, validator := range bls {
+		log.Warnf("Validator %s has BLS (0x00) withdrawal credentials %s and needs withdrawal credential update to receive automatic withdrawals.",
+			validatorName(validator.Index, hexutil.Encode(validator.Validator.PublicKey[:])), hexutil.Encode(validator.Validator.WithdrawalCredentials))
+	}
+	log.Infof("%d of %d validator(s) need a withdrawal credential update.", len(bls), len(validators))
+	return nil
+}
+
+// blsCredentialValidators returns the validators that have BLS (0x00) rather than execution (0x01) withdrawal credentials.
+func blsCredentialValidators(validators []*apiv1.Validator) []*apiv1.Validator {
+	bls := make([]*apiv1.Validator, 0)
+	for _, validator := range validators {
+		if len(validator.Validator.WithdrawalCredentials) > 0 && validator.Validator.WithdrawalCredentials[0] == 0x00 {
+			bls = append(bls, validator)
+		}
+	}
+	sort.Slice(bls, func(i int, j int) bool {
+		return bls[i].Index < bls[j].Index
+	})
+	return bls
+}
+
+// Proposals lists the upcoming proposal slots for the validators from the current epoch up to the given epoch.
+func Proposals(validatorsStr []string, epoch string) error {
+	if len(validatorsStr) == 0 {
+		return fmt.Errorf("at least 1 validator index or public key must be specified to retrieve proposals for")
+	}
+	if err := Init(); err != nil {
+		return err
+	}
+	endEpoch, err := chaintime.ParseEpoch(chainTime, epoch)
+	if err != nil {
+		return err
+	}
+	startEpoch := chainTime.CurrentEpoch()
+	if endEpoch < startEpoch {
+		startEpoch = endEpoch
+	}
+	validators, err := parseValidators(blockchain.Ctx, validatorsStr, "head")
+	if err != nil {
+		return err
+	}
+	validatorsByIndex := make(map[phase0.ValidatorIndex]*apiv1.Validator)
+	for _, validator := range validators {
+		validatorsByIndex[validator.Index] = validator
+	}
+
+	count := 0
+	for e := startEpoch; e <= endEpoch; e++ {
+		response, err := proposerDuties(e)
+		if err != nil {
+			return util.WrapError(err, "failed to obtain proposer duties for epoch %d", e)
+		}
+		for _, duty := range response.Data {
+			validator, exists := validatorsByIndex[duty.ValidatorIndex]
+			if !exists || duty.Slot < chainTime.CurrentSlot() {
+				continue
+			}
+			count++
+			start := chainTime.StartOfSlot(duty.Slot)
+			log.Infof("Slot %d (epoch %d) at %v (in %v): validator %s", duty.Slot, e, start, time.Until(start).Round(time.Second), validatorName(validator.Index, hexutil.Encode(validator.Validator.PublicKey[:])))
+		}
+	}
+	if count == 0 {
+		log.Infof("No upcoming proposals for the validator(s) up to epoch %d.", endEpoch)
+	}
+	return nil
+}
+
+// ProposalHistory lists for each epoch in the range the proposal slots of the validators and whether a block was
+// produced at each, fetching only the proposer duties and blocks and not the attestations.
+func ProposalHistory(validatorsStr []string, start string, end string, num string) error {
+	if len(validatorsStr) == 0 {
+		return fmt.Errorf("at least 1 validator index or public key must be specified to retrieve proposals for")
+	}
+	if err := Init(); err != nil {
+		return err
+	}
+	startEpoch, endEpoch, err := parseEpochRange(start, end, num)
+	if err != nil {
+		return err
+	}
+	validators, err := parseValidators(blockchain.Ctx, validatorsStr, "head")
+	if err != nil {
+		return err
+	}
+	validatorsByIndex := make(map[phase0.ValidatorIndex]*apiv1.Validator)
+	for _, validator := range validators {
+		validatorsByIndex[validator.Index] = validator
+	}
+
+	log.Infof("fetching proposals for start epoch: %v, end epoch: %v.", startEpoch, endEpoch)
+	count, missed := 0, 0
+	for epoch := startEpoch; epoch <= endEpoch; epoch++ {
+		summary := &validatorSummary{Epoch: epoch}
+		if err := processProposerDuties(validatorsByIndex, summary); err != nil {
+			return util.WrapError(err, "could not process proposer duties for epoch %d", epoch)
+		}
+		for _, p := range summary.Proposals {
+			name := validatorName(p.Proposer, hexutil.Encode(validatorsByIndex[p.Proposer].Validator.PublicKey[:]))
+			switch {
+			case p.Slot > chainTime.CurrentSlot():
+				log.Infof("Epoch %d slot %d: validator %s, upcoming.", epoch, p.Slot, name)
+				continue
+			case p.Block:
+				log.Infof("Epoch %d slot %d: validator %s, block produced.", epoch, p.Slot, name)
+			default:
+				log.Infof("Epoch %d slot %d: validator %s, missed.", epoch, p.Slot, name)
+				missed++
+			}
+			count++
+		}
+	}
+	log.Infof("%d proposal(s) for epochs %v to %v, %d missed.", count, startEpoch, endEpoch, missed)
+	return nil
+}
+
+// Watch polls the status of a validator every epoch and logs each status transition until interrupted.
+func Watch(validatorStr string) error {
+	if err := Init(); err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Infof("Watching status of validator %s. Press Ctrl-C to stop.", validatorStr)
+	var status apiv1.ValidatorState
+	known := false
+	for {
+		epoch := chainTime.CurrentEpoch()
+		validator, err := parseValidator(ctx, validatorsProvider, validatorStr, "head")
+		if err != nil && ctx.Err() == nil {
+			log.Errorf("Could not get status of validator %s at epoch %d: %v", validatorStr, epoch, err)
+		} else if err == nil && !known {
+			log.Infof("Epoch %d (%v): validator %s status is %s.", epoch, chainTime.StartOfEpoch(epoch), validatorName(validator.Index, hexutil.Encode(validator.Validator.PublicKey[:])), validator.Status)
+			status, known = validator.Status, true
+		} else if err == nil && validator.Status != status {
+			log.Infof("Epoch %d (%v): validator %s status changed from %s to %s.", epoch, chainTime.StartOfEpoch(epoch), validatorName(validator.Index, hexutil.Encode(validator.Validator.PublicKey[:])), status, validator.Status)
+			status = validator.Status
+		}
+
+		if err := chainTime.WaitUntilEpoch(ctx, epoch+1); err != nil {
+			log.Infof("Stopped watching validator %s.", validatorStr)
+			return nil
 		}
-		response, err := provider.Validators(blockchain.Ctx, &api.ValidatorsOpts{PubKeys: []phase0.BLSPubKey{k}, State: "head"})
+	}
+}
+
+// DumpSet writes the entire validator set at the given state to a JSON file, streaming each validator to the file as it is serialized.
+func DumpSet(stateID string, out string) error {
+	provider, isProvider := blockchain.BeaconClient.(eth2client.ValidatorsProvider)
+	if !isProvider {
+		return fmt.Errorf("could not get validator interface")
+	}
+	log.Infof("Fetching validator set at state %s...", stateID)
+	response, err := provider.Validators(blockchain.Ctx, &api.ValidatorsOpts{State: stateID})
+	if err != nil {
+		return util.WrapError(err, "failed to obtain validator set")
+	}
+	indices := make([]phase0.ValidatorIndex, 0, len(response.Data))
+	for index := range response.Data {
+		indices = append(indices, index)
+	}
+	sort.Slice(indices, func(i int, j int) bool {
+		return indices[i] < indices[j]
+	})
+
+	f, err := os.Create(out)
+	if err != nil {
+		return util.WrapError(err, "could not create file %s", out)
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	if _, err := w.WriteString("[\n"); err != nil {
+		return err
+	}
+	for i, index := range indices {
+		data, err := json.Marshal(newValidatorInfo(response.Data[index]))
 		if err != nil {
+			return util.WrapError(err, "could not serialize validator %d", index)
+		}
+		if i > 0 {
+			if _, err := w.WriteString(",\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(data); err != nil {
 			return err
-		} else if len(response.Data) == 0 {
-			return fmt.Errorf("could not retrieve info on validator with publick key %v", validatorPubKey)
-		} else {
-			for _, v := range response.Data {
-				log.Infof("Validator index: %v", v.Index)
-				log.Infof("Validator public key: %v", hexutil.Encode(v.Validator.PublicKey[:]))
-				log.Infof("Validator activation eligibility epoch: %v", v.Validator.ActivationEligibilityEpoch)
-				log.Infof("Validator activation epoch: %v", v.Validator.ActivationEpoch)
-				log.Infof("Validator effective balance: %v", v.Validator.EffectiveBalance/1000000000)
-				log.Infof("Validator withdrawal credentials: %v", hexutil.Encode(v.Validator.WithdrawalCredentials))
+		}
+	}
+	if _, err := w.WriteString("\n]\n"); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return util.WrapError(err, "could not write file %s", out)
+	}
+	// Closing reports write errors the flush did not e.g. a full disk on some filesystems. The deferred close is then a no-op.
+	if err := f.Close(); err != nil {
+		return util.WrapError(err, "could not write file %s", out)
+	}
+	log.Infof("Wrote %d validators to %s.", len(indices), out)
+	return nil
+}
+
+// validatorStatusGroups are the status names that match several validator states.
+var validatorStatusGroups = map[string]func(v *apiv1.Validator) bool{
+	"pending":    func(v *apiv1.Validator) bool { return v.Status.IsPending() },
+	"active":     func(v *apiv1.Validator) bool { return v.Status.IsActive() },
+	"exited":     func(v *apiv1.Validator) bool { return v.Status.IsExited() },
+	"withdrawal": func(v *apiv1.Validator) bool { return v.Status.HasExited() && !v.Status.IsExited() },
+	"slashed":    func(v *apiv1.Validator) bool { return v.Validator.Slashed },
+}
+
+// List prints the index, public key, balance and status of the validators in the validator set at a state that have
+// any of the statuses, in index order up to the limit. A status can be a validator state e.g. active_ongoing or one of
+// pending, active, exited, withdrawal or slashed.
+func List(statuses []string, stateID string, limit int) error {
+	if limit < 0 {
+		return fmt.Errorf("the limit must not be negative")
+	}
+	matchers := make([]func(v *apiv1.Validator) bool, 0, len(statuses))
+	for _, status := range statuses {
+		status = strings.ToLower(strings.TrimSpace(status))
+		if status == "" {
+			continue
+		}
+		if group, exists := validatorStatusGroups[status]; exists {
+			matchers = append(matchers, group)
+			continue
+		}
+		var state apiv1.ValidatorState
+		if err := state.UnmarshalJSON([]byte(fmt.Sprintf("%q", status))); err != nil {
+			return fmt.Errorf("unknown validator status %s: the status must be a validator state e.g. active_ongoing or one of pending, active, exited, withdrawal or slashed", status)
+		}
+		matchers = append(matchers, func(v *apiv1.Validator) bool { return v.Status == state })
+	}
+	if err := Init(); err != nil {
+		return err
+	}
+	log.Infof("Fetching validator set at state %s...", stateID)
+	response, err := validatorsProvider.Validators(blockchain.Ctx, &api.ValidatorsOpts{State: stateID})
+	if err != nil {
+		return util.WrapError(err, "failed to obtain validator set")
+	}
+	matched := make([]*apiv1.Validator, 0)
+	for _, v := range response.Data {
+		for _, matches := range matchers {
+			if matches(v) {
+				matched = append(matched, v)
+				break
 			}
 		}
+		if len(matchers) == 0 {
+			matched = append(matched, v)
+		}
+	}
+	sort.Slice(matched, func(i int, j int) bool {
+		return matched[i].Index < matched[j].Index
+	})
+
+	shown := matched
+	if limit > 0 && len(matched) > limit {
+		shown = matched[:limit]
+	}
+	infos := make([]*validatorInfo, 0, len(shown))
+	for _, v := range shown {
+		infos = append(infos, newValidatorInfo(v))
+	}
+	err = util.Render(infos, func() {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "INDEX\tPUBKEY\tBALANCE\tSTATUS")
+		for _, v := range infos {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%v\n", v.Index, v.PubKey, gweiString(uint64(v.Balance)), v.Status)
+		}
+		w.Flush()
+	})
+	if err != nil {
+		return err
+	}
+	if len(shown) < len(matched) {
+		log.Infof("Showing %d of %d matching validators out of %d.", len(shown), len(matched), len(response.Data))
 	} else {
-		return fmt.Errorf("could not get validator interface")
+		log.Infof("%d matching validators out of %d.", len(matched), len(response.Data))
 	}
 	return nil
 }
 
+func newValidatorInfo(v *apiv1.Validator) *validatorInfo {
+	pubKey := hexutil.Encode(v.Validator.PublicKey[:])
+	return &validatorInfo{
+		Index:                      v.Index,
+		Label:                      labelFor(v.Index, pubKey),
+		PubKey:                     pubKey,
+		Status:                     v.Status.String(),
+		ActivationEligibilityEpoch: v.Validator.ActivationEligibilityEpoch,
+		ActivationEpoch:            v.Validator.ActivationEpoch,
+		ExitEpoch:                  v.Validator.ExitEpoch,
+		WithdrawableEpoch:          v.Validator.WithdrawableEpoch,
+		ActivationEligibilityTime:  epochTime(v.Validator.ActivationEligibilityEpoch),
+		ActivationTime:             epochTime(v.Validator.ActivationEpoch),
+		ExitTime:                   epochTime(v.Validator.ExitEpoch),
+		WithdrawableTime:           epochTime(v.Validator.WithdrawableEpoch),
+		Balance:                    v.Balance,
+		EffectiveBalance:           v.Validator.EffectiveBalance,
+		WithdrawalCredentials:      hexutil.Encode(v.Validator.WithdrawalCredentials),
+	}
+}
+
+// epochTime is the start of an epoch, or nil for the far future epoch.
+func epochTime(epoch phase0.Epoch) *time.Time {
+	if epoch == farFutureEpoch || chainTime == nil {
+		return nil
+	}
+	t := chainTime.StartOfEpoch(epoch)
+	return &t
+}
+
+// epochString formats a lifecycle epoch with its approximate time, or n/a for the far future epoch.
+func epochString(epoch phase0.Epoch, t *time.Time) string {
+	if epoch == farFutureEpoch {
+		return "n/a"
+	} else if t == nil {
+		return fmt.Sprintf("%d", epoch)
+	}
+	return fmt.Sprintf("%d (approx. %v)", epoch, *t)
+}
+
+func logValidatorInfo(v *validatorInfo) {
+	log.Infof("Validator index: %v", v.Index)
+	if v.Label != "" {
+		log.Infof("Validator label: %v", v.Label)
+	}
+	log.Infof("Validator public key: %v", v.PubKey)
+	log.Infof("Validator status: %v", v.Status)
+	log.Infof("Validator activation eligibility epoch: %s", epochString(v.ActivationEligibilityEpoch, v.ActivationEligibilityTime))
+	log.Infof("Validator activation epoch: %s", epochString(v.ActivationEpoch, v.ActivationTime))
+	log.Infof("Validator exit epoch: %s", epochString(v.ExitEpoch, v.ExitTime))
+	log.Infof("Validator withdrawable epoch: %s", epochString(v.WithdrawableEpoch, v.WithdrawableTime))
+	log.Infof("Validator balance: %v", v.Balance/1000000000)
+	log.Infof("Validator effective balance: %v", v.EffectiveBalance/1000000000)
+	log.Infof("Validator withdrawal credentials: %v", v.WithdrawalCredentials)
+}
+
+// parseValidatorRange parses a range of validator indices with an optional step e.g. 1000-2000:10.
+func parseValidatorRange(s string) ([]phase0.ValidatorIndex, error) {
+	r, stepStr, hasStep := strings.Cut(s, ":")
+	bits := strings.Split(r, "-")
+	if len(bits) != 2 {
+		return nil, fmt.Errorf("invalid range %s", s)
+	}
+	low, err := strconv.ParseUint(bits[0], 10, 64)
+	if err != nil {
+		return nil, util.WrapError(err, "invalid range start")
+	}
+	high, err := strconv.ParseUint(bits[1], 10, 64)
+	if err != nil {
+		return nil, util.WrapError(err, "invalid range end")
+	}
+	if low > high {
+		return nil, fmt.Errorf("invalid range %s: the start is greater than the end", s)
+	}
+	step := uint64(1)
+	if hasStep {
+		if step, err = strconv.ParseUint(stepStr, 10, 64); err != nil || step == 0 {
+			return nil, fmt.Errorf("invalid range step %s: the step must be a positive integer", stepStr)
+		}
+	}
+	indices := make([]phase0.ValidatorIndex, 0)
+	// The loop stops before the next step would pass the end, so index can't overflow near math.MaxUint64.
+	for index := low; ; index += step {
+		indices = append(indices, phase0.ValidatorIndex(index))
+		if high-index < step {
+			break
+		}
+	}
+	return indices, nil
+}
+
 // ParseValidators parses input to obtain the list of validators.
 func parseValidators(ctx context.Context, validatorsStr []string, stateID string) ([]*apiv1.Validator, error) {
 	validators := make([]*apiv1.Validator, 0, len(validatorsStr))
 	indices := make([]phase0.ValidatorIndex, 0)
+	pubKeys := make([]phase0.BLSPubKey, 0)
 	for i := range validatorsStr {
-		if strings.Contains(validatorsStr[i], "-") {
-			// Range.
-			bits := strings.Split(validatorsStr[i], "-")
-			if len(bits) != 2 {
-				return nil, fmt.Errorf("invalid range %s", validatorsStr[i])
-			}
-			low, err := strconv.ParseUint(bits[0], 10, 64)
+		if strings.HasPrefix(validatorsStr[i], "0x") {
+			pubKey, err := util.ToPubKey(validatorsStr[i])
 			if err != nil {
-				return nil, util.WrapError(err, "invalid range start")
+				return nil, fmt.Errorf("invalid validator public key %s: the public key must be 48 bytes", validatorsStr[i])
 			}
-			high, err := strconv.ParseUint(bits[1], 10, 64)
+			pubKeys = append(pubKeys, pubKey)
+		} else if strings.Contains(validatorsStr[i], "-") {
+			rangeIndices, err := parseValidatorRange(validatorsStr[i])
 			if err != nil {
-				return nil, util.WrapError(err, "invalid range end")
-			}
-			for index := low; index <= high; index++ {
-				indices = append(indices, phase0.ValidatorIndex(index))
+				return nil, err
 			}
+			indices = append(indices, rangeIndices...)
 		} else {
 			index, err := strconv.ParseUint(validatorsStr[i], 10, 64)
 			if err != nil {
@@ -399,11 +1337,34 @@ func parseValidators(ctx context.Context, validatorsStr []string, stateID string
 		}
 	}
 
-	response, err := validatorsProvider.Validators(ctx, &api.ValidatorsOpts{State: stateID, Indices: indices})
-	if err != nil {
-		return nil, util.WrapError(err, fmt.Sprintf("failed to obtain validators %v", indices))
+	// The client doesn't accept indices and public keys in the same request so each is fetched separately.
+	found := make(map[phase0.ValidatorIndex]*apiv1.Validator)
+	if len(indices) > 0 {
+		response, err := validatorsProvider.Validators(ctx, &api.ValidatorsOpts{State: stateID, Indices: indices})
+		if err != nil {
+			return nil, util.WrapError(err, fmt.Sprintf("failed to obtain validators %v", indices))
+		}
+		for index, validator := range response.Data {
+			found[index] = validator
+		}
 	}
-	for _, validator := range response.Data {
+	if len(pubKeys) > 0 {
+		response, err := validatorsProvider.Validators(ctx, &api.ValidatorsOpts{State: stateID, PubKeys: pubKeys})
+		if err != nil {
+			return nil, util.WrapError(err, "failed to obtain validators by public key")
+		}
+		byPubKey := make(map[phase0.BLSPubKey]struct{}, len(response.Data))
+		for index, validator := range response.Data {
+			found[index] = validator
+			byPubKey[validator.Validator.PublicKey] = struct{}{}
+		}
+		for _, pubKey := range pubKeys {
+			if _, exists := byPubKey[pubKey]; !exists {
+				return nil, fmt.Errorf("no validator found with public key %#x", pubKey)
+			}
+		}
+	}
+	for _, validator := range found {
 		validators = append(validators, validator)
 	}
 	return validators, nil
@@ -455,9 +1416,7 @@ func parseValidator(ctx context.Context,
 }
 
 func processProposerDuties(validatorsByIndex map[phase0.ValidatorIndex]*apiv1.Validator, summary *validatorSummary) error {
-	response, err := pdProvider.ProposerDuties(blockchain.Ctx, &api.ProposerDutiesOpts{
-		Epoch: summary.Epoch,
-	})
+	response, err := proposerDuties(summary.Epoch)
 	if err != nil {
 		return util.WrapError(err, "failed to obtain proposer duties")
 	}
@@ -465,24 +1424,34 @@ func processProposerDuties(validatorsByIndex map[phase0.ValidatorIndex]*apiv1.Va
 		if _, exists := validatorsByIndex[duty.ValidatorIndex]; !exists {
 			continue
 		}
-		blockResponse, err := blocksProvider.SignedBeaconBlock(blockchain.Ctx, &api.SignedBeaconBlockOpts{
-			Block: fmt.Sprintf("%d", duty.Slot),
-		})
+		blockResponse, err := signedBeaconBlock(duty.Slot)
+		proposal := &epochProposal{
+			Slot:     duty.Slot,
+			Proposer: duty.ValidatorIndex,
+		}
 		if err != nil {
 			var apiErr *api.Error
-			if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
-				return nil
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+				return errors.Wrap(err, fmt.Sprintf("failed to obtain block for slot %d", duty.Slot))
+			}
+			// No block at the slot so the proposal was missed.
+		} else if blockResponse.Data != nil {
+			proposal.Block = true
+			if g, err := blockResponse.Data.Graffiti(); err == nil {
+				proposal.Graffiti = util.GraffitiString(g)
+			}
+			if attestations, err := blockResponse.Data.Attestations(); err == nil {
+				proposal.Attestations = len(attestations)
+				for _, attestation := range attestations {
+					proposal.AttestingBits += int(attestation.AggregationBits.Count())
+				}
+			}
+			if aggregate, err := blockResponse.Data.SyncAggregate(); err == nil {
+				proposal.SyncBits = int(aggregate.SyncCommitteeBits.Count())
+				proposal.SyncSize = int(aggregate.SyncCommitteeBits.Len())
 			}
-
-			return errors.Wrap(err, fmt.Sprintf("failed to obtain block for slot %d", duty.Slot))
 		}
-		block := blockResponse.Data
-		present := block != nil
-		summary.Proposals = append(summary.Proposals, &epochProposal{
-			Slot:     duty.Slot,
-			Proposer: duty.ValidatorIndex,
-			Block:    present,
-		})
+		summary.Proposals = append(summary.Proposals, proposal)
 	}
 
 	return nil
@@ -501,7 +1470,7 @@ func getActiveValidators(validatorsByIndex map[phase0.ValidatorIndex]*apiv1.Vali
 	return activeValidators, activeValidatorIndices
 }
 
-func processAttesterDuties(validatorsByIndex map[phase0.ValidatorIndex]*apiv1.Validator, summary *validatorSummary) error {
+func processAttesterDuties(validatorsByIndex map[phase0.ValidatorIndex]*apiv1.Validator, summary *validatorSummary, firstSlotOnly bool) error {
 	activeValidators, activeValidatorIndices := getActiveValidators(validatorsByIndex, summary)
 
 	// Obtain number of validators that voted for blocks in the epoch.
@@ -509,15 +1478,17 @@ func processAttesterDuties(validatorsByIndex map[phase0.ValidatorIndex]*apiv1.Va
 	// the epoch to the first slot of the next-but-one epoch.
 	firstSlot := chainTime.FirstSlotOfEpoch(summary.Epoch) + 1
 	lastSlot := chainTime.FirstSlotOfEpoch(summary.Epoch + 2)
+	if firstSlotOnly {
+		// Most votes are included in the slot after they are made, so only scan
+		// the epoch and the first couple of slots of the next epoch.
+		lastSlot = chainTime.FirstSlotOfEpoch(summary.Epoch+1) + 1
+	}
 	if lastSlot > chainTime.CurrentSlot() {
 		lastSlot = chainTime.CurrentSlot()
 	}
 
 	// Obtain the duties for the validators to know where they should be attesting.
-	dutiesResponse, err := attesterDutiesProvider.AttesterDuties(blockchain.Ctx, &api.AttesterDutiesOpts{
-		Epoch:   summary.Epoch,
-		Indices: activeValidatorIndices,
-	})
+	dutiesResponse, err := attesterDuties(summary.Epoch, activeValidatorIndices)
 	if err != nil {
 		return errors.Wrap(err, "failed to obtain attester duties")
 	}
@@ -528,7 +1499,7 @@ func processAttesterDuties(validatorsByIndex map[phase0.ValidatorIndex]*apiv1.Va
 	}
 
 	// Need a cache of beacon block headers to reduce lookup times.
-	headersCache := util.NewBeaconBlockHeaderCache(beaconBlockHeadersProvider)
+	headersCache := util.NewBeaconBlockHeaderCache(beaconBlockHeadersProvider, util.HeaderCacheSize)
 
 	// Need a map of duties to easily find the attestations we care about.
 	dutiesBySlot := make(map[phase0.Slot]map[phase0.CommitteeIndex][]*apiv1.AttesterDuty)
@@ -554,18 +1525,27 @@ func processAttesterDuties(validatorsByIndex map[phase0.ValidatorIndex]*apiv1.Va
 	summary.UntimelyTargetValidators = make([]*validatorFault, 0)
 
 	// Hunt through the blocks looking for attestations from the validators.
-	votes := make(map[phase0.ValidatorIndex]struct{})
-	for slot := firstSlot; slot <= lastSlot; slot++ {
-		if err := processAttesterDutiesSlot(slot, dutiesBySlot, votes, headersCache, activeValidatorIndices, summary); err != nil {
+	// votes holds the inclusion distance of the attestation of each validator that voted.
+	votes := make(map[phase0.ValidatorIndex]int)
+	attestations, err := blockAttestations(firstSlot, lastSlot)
+	if err != nil {
+		return err
+	}
+	// The blocks are processed in slot order so an attestation included more than once is counted at its first
+	// inclusion and the results are the same however the blocks were fetched.
+	for i, slotAttestations := range attestations {
+		if err := processAttesterDutiesSlot(firstSlot+phase0.Slot(i), slotAttestations, dutiesBySlot, votes, headersCache, activeValidatorIndices, summary, firstSlotOnly); err != nil {
 			return err
 		}
 	}
+	hits, misses, evictions := headersCache.Stats()
+	log.Debugf("Beacon block header cache for epoch %d: %d hits, %d misses, %d evictions.", summary.Epoch, hits, misses, evictions)
 
 	// Use dutiesMap and votes to work out which validators didn't participate.
 	summary.NonParticipatingValidators = make([]*nonParticipatingValidator, 0)
 	for _, index := range activeValidatorIndices {
 		duty := dutiesByValidatorIndex[index]
-		if _, exists := votes[index]; !exists {
+		if distance, exists := votes[index]; !exists {
 			// Didn't vote.
 			summary.NonParticipatingValidators = append(summary.NonParticipatingValidators, &nonParticipatingValidator{
 				Validator: index,
@@ -573,11 +1553,15 @@ func processAttesterDuties(validatorsByIndex map[phase0.ValidatorIndex]*apiv1.Va
 				Committee: duty.CommitteeIndex,
 			})
 		} else {
-			summary.AttestingValidators = append(summary.AttestingValidators, &attestingValidator{
+			v := &attestingValidator{
 				Validator: validatorsByIndex[index],
 				Slot:      duty.Slot,
 				Committee: duty.CommitteeIndex,
-			})
+			}
+			if !firstSlotOnly {
+				v.InclusionDistance = distance
+			}
+			summary.AttestingValidators = append(summary.AttestingValidators, v)
 		}
 	}
 
@@ -597,30 +1581,69 @@ func processAttesterDuties(validatorsByIndex map[phase0.ValidatorIndex]*apiv1.Va
 	return nil
 }
 
+// blockAttestations fetches the attestations included in the blocks from firstSlot to lastSlot, in slot order. Slots
+// without a block have no attestations. Epoch summaries are computed holding a slot of util.Concurrency, so the blocks
+// are fetched by the caller plus a worker for each other free slot. A single epoch is then scanned concurrently while
+// scans of many epochs at once stay within the concurrency bound.
+func blockAttestations(firstSlot phase0.Slot, lastSlot phase0.Slot) ([][]*phase0.Attestation, error) {
+	if lastSlot < firstSlot {
+		return nil, nil
+	}
+	n := int(lastSlot-firstSlot) + 1
+	results := make([][]*phase0.Attestation, n)
+	errs := make([]error, n)
+	indices := make(chan int, n)
+	for i := 0; i < n; i++ {
+		indices <- i
+	}
+	close(indices)
+	fetch := func() {
+		for i := range indices {
+			results[i], errs[i] = slotBlockAttestations(firstSlot + phase0.Slot(i))
+		}
+	}
+	wg := new(sync.WaitGroup)
+	for workers := 1; workers < n && util.Concurrency.TryAcquire(); workers++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer util.Concurrency.Release()
+			fetch()
+		}()
+	}
+	fetch()
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+func slotBlockAttestations(slot phase0.Slot) ([]*phase0.Attestation, error) {
+	blockResponse, err := signedBeaconBlock(slot)
+	if err != nil {
+		var apiErr *api.Error
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "failed to obtain beacon block")
+	}
+	return blockResponse.Data.Attestations()
+}
+
 func processAttesterDutiesSlot(
 	slot phase0.Slot,
+	attestations []*phase0.Attestation,
 	dutiesBySlot map[phase0.Slot]map[phase0.CommitteeIndex][]*apiv1.AttesterDuty,
-	votes map[phase0.ValidatorIndex]struct{},
+	votes map[phase0.ValidatorIndex]int,
 	headersCache *util.BeaconBlockHeaderCache,
 	activeValidatorIndices []phase0.ValidatorIndex,
 	summary *validatorSummary,
+	firstSlotOnly bool,
 ) error {
-	blockResponse, err := blocksProvider.SignedBeaconBlock(blockchain.Ctx, &api.SignedBeaconBlockOpts{
-		Block: fmt.Sprintf("%d", slot),
-	})
-	if err != nil {
-		var apiErr *api.Error
-		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
-			return nil
-		}
-
-		return errors.Wrap(err, "failed to obtain beacon block")
-	}
-	block := blockResponse.Data
-	attestations, err := block.Attestations()
-	if err != nil {
-		return err
-	}
 	for _, attestation := range attestations {
 		if _, exists := dutiesBySlot[attestation.Data.Slot]; !exists {
 			// We do not have any attestations for this slot.
@@ -637,12 +1660,16 @@ func processAttesterDutiesSlot(
 					// Duplicate; ignore.
 					continue
 				}
-				votes[duty.ValidatorIndex] = struct{}{}
+				votes[duty.ValidatorIndex] = int(slot - duty.Slot)
 
 				// Update the metrics for the attestation.
 				index := int(attestation.Data.Slot - chainTime.FirstSlotOfEpoch(summary.Epoch))
 				summary.Slots[index].Attestations.Included++
+				if firstSlotOnly {
+					continue
+				}
 				inclusionDelay := slot - duty.Slot
+				summary.Slots[index].Attestations.TotalInclusionDistance += int(inclusionDelay)
 
 				fault := &validatorFault{
 					Validator:         duty.ValidatorIndex,
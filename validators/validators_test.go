@@ -0,0 +1,48 @@
+package validators
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+func TestParseValidatorRange(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []phase0.ValidatorIndex
+		err  bool
+	}{
+		{in: "5-5", want: []phase0.ValidatorIndex{5}},
+		{in: "1-4", want: []phase0.ValidatorIndex{1, 2, 3, 4}},
+		{in: "1000-1030:10", want: []phase0.ValidatorIndex{1000, 1010, 1020, 1030}},
+		{in: "1000-1035:10", want: []phase0.ValidatorIndex{1000, 1010, 1020, 1030}},
+		{in: "0-3:100", want: []phase0.ValidatorIndex{0}},
+		// The last index is close enough to math.MaxUint64 that another step would overflow.
+		{in: fmt.Sprintf("%d-%d:2", uint64(math.MaxUint64)-3, uint64(math.MaxUint64)), want: []phase0.ValidatorIndex{math.MaxUint64 - 3, math.MaxUint64 - 1}},
+		{in: fmt.Sprintf("%d-%d", uint64(math.MaxUint64)-1, uint64(math.MaxUint64)), want: []phase0.ValidatorIndex{math.MaxUint64 - 1, math.MaxUint64}},
+		{in: "2000-1000:10", err: true},
+		{in: "2-1", err: true},
+		{in: "1-2:0", err: true},
+		{in: "1-2:x", err: true},
+		{in: "1-2-3", err: true},
+		{in: "a-2", err: true},
+		{in: "1-", err: true},
+	}
+	for _, tt := range tests {
+		got, err := parseValidatorRange(tt.in)
+		if tt.err {
+			if err == nil {
+				t.Errorf("parseValidatorRange(%q) = %v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseValidatorRange(%q) returned error: %v", tt.in, err)
+		} else if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseValidatorRange(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
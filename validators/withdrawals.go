@@ -0,0 +1,152 @@
+package validators
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	api "github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+
+	"github.com/allisterb/strac/blockchain"
+	"github.com/allisterb/strac/util"
+)
+
+// Withdrawals reports whether each validator is due a withdrawal in the withdrawals sweep and the withdrawals made to
+// the validators in the epoch range, or if no range is given in the most recent number of slots, with the total
+// withdrawn by each validator.
+func Withdrawals(validatorsStr []string, start string, end string, num string, slots uint64) error {
+	if len(validatorsStr) == 0 {
+		return fmt.Errorf("at least 1 validator index or public key must be specified to retrieve withdrawals for")
+	}
+	if err := Init(); err != nil {
+		return err
+	}
+	var firstSlot, lastSlot phase0.Slot
+	if start != "" || end != "" || num != "" {
+		startEpoch, endEpoch, err := parseEpochRange(start, end, num)
+		if err != nil {
+			return err
+		}
+		if firstSlot, lastSlot, err = slotRange(startEpoch, endEpoch); err != nil {
+			return err
+		}
+	} else {
+		if slots == 0 {
+			return fmt.Errorf("at least 1 slot must be scanned")
+		}
+		lastSlot = chainTime.CurrentSlot()
+		if uint64(lastSlot) >= slots {
+			firstSlot = lastSlot - phase0.Slot(slots) + 1
+		}
+	}
+	validators, err := parseValidators(blockchain.Ctx, validatorsStr, "head")
+	if err != nil {
+		return err
+	}
+	sort.Slice(validators, func(i int, j int) bool {
+		return validators[i].Index < validators[j].Index
+	})
+	maxEffectiveBalance, err := maxEffectiveBalance()
+	if err != nil {
+		return err
+	}
+
+	epoch := chainTime.CurrentEpoch()
+	indices := make(map[phase0.ValidatorIndex]struct{})
+	for _, validator := range validators {
+		indices[validator.Index] = struct{}{}
+		name := validatorName(validator.Index, hexutil.Encode(validator.Validator.PublicKey[:]))
+		credentials := validator.Validator.WithdrawalCredentials
+		switch {
+		case len(credentials) == 0 || credentials[0] != 0x01:
+			log.Warnf("Validator %s does not have execution (0x01) withdrawal credentials and is not eligible for withdrawals.", name)
+		case validator.Validator.WithdrawableEpoch <= epoch && validator.Balance > 0:
+			log.Infof("Validator %s is queued for a full withdrawal of %v Gwei to %v.", name, validator.Balance, hexutil.Encode(credentials[12:]))
+		case validator.Validator.EffectiveBalance == maxEffectiveBalance && validator.Balance > maxEffectiveBalance:
+			log.Infof("Validator %s is queued for a partial withdrawal of %v Gwei to %v.", name, validator.Balance-maxEffectiveBalance, hexutil.Encode(credentials[12:]))
+		default:
+			log.Infof("Validator %s has no pending withdrawal.", name)
+		}
+	}
+
+	log.Infof("Scanning slots %d to %d in epochs %v to %v for withdrawals...", firstSlot, lastSlot, chainTime.SlotToEpoch(firstSlot), chainTime.SlotToEpoch(lastSlot))
+	withdrawals, err := withdrawalsInRange(indices, firstSlot, lastSlot)
+	if err != nil {
+		return err
+	}
+	counts := make(map[phase0.ValidatorIndex]int)
+	totals := make(map[phase0.ValidatorIndex]phase0.Gwei)
+	var total phase0.Gwei
+	for _, w := range withdrawals {
+		log.Infof("Slot %d: validator %s withdrew %v Gwei to %v.", w.Slot, validatorName(w.Validator, pubKeyOf(validators, w.Validator)), w.Amount, w.Address)
+		counts[w.Validator]++
+		totals[w.Validator] += w.Amount
+		total += w.Amount
+	}
+	for _, validator := range validators {
+		if counts[validator.Index] > 0 {
+			name := validatorName(validator.Index, hexutil.Encode(validator.Validator.PublicKey[:]))
+			log.Infof("Validator %s: %d withdrawal(s) totalling %s.", name, counts[validator.Index], gweiString(uint64(totals[validator.Index])))
+		}
+	}
+	log.Infof("%d withdrawal(s) to %d validator(s) in slots %d to %d totalling %s.", len(withdrawals), len(counts), firstSlot, lastSlot, gweiString(uint64(total)))
+	return nil
+}
+
+func maxEffectiveBalance() (phase0.Gwei, error) {
+	specResponse, err := specProvider.Spec(blockchain.Ctx, &api.SpecOpts{})
+	if err != nil {
+		return 0, util.WrapError(err, "failed to obtain spec")
+	}
+	v, ok := specResponse.Data["MAX_EFFECTIVE_BALANCE"].(uint64)
+	if !ok {
+		return 0, fmt.Errorf("MAX_EFFECTIVE_BALANCE not found in spec")
+	}
+	return phase0.Gwei(v), nil
+}
+
+type processedWithdrawal struct {
+	Slot      phase0.Slot
+	Validator phase0.ValidatorIndex
+	Address   string
+	Amount    phase0.Gwei
+}
+
+// withdrawalsInRange concurrently scans the blocks in the slot range for withdrawals to the validators.
+func withdrawalsInRange(indices map[phase0.ValidatorIndex]struct{}, firstSlot phase0.Slot, lastSlot phase0.Slot) ([]*processedWithdrawal, error) {
+	return scanSlots(firstSlot, lastSlot, func(slot phase0.Slot) ([]*processedWithdrawal, error) {
+		return slotWithdrawals(indices, slot)
+	})
+}
+
+func slotWithdrawals(indices map[phase0.ValidatorIndex]struct{}, slot phase0.Slot) ([]*processedWithdrawal, error) {
+	blockResponse, err := signedBeaconBlock(slot)
+	if err != nil {
+		var apiErr *api.Error
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			// No block so no withdrawals.
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to obtain block for slot %d", slot))
+	}
+	withdrawals, err := blockResponse.Data.Withdrawals()
+	if err != nil {
+		// Blocks before Capella have no withdrawals.
+		return nil, nil
+	}
+	results := make([]*processedWithdrawal, 0)
+	for _, w := range withdrawals {
+		if _, exists := indices[w.ValidatorIndex]; exists {
+			results = append(results, &processedWithdrawal{
+				Slot:      slot,
+				Validator: w.ValidatorIndex,
+				Address:   w.Address.String(),
+				Amount:    w.Amount,
+			})
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,194 @@
+package wallets
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gethaccounts "github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/tyler-smith/go-bip39"
+
+	"github.com/allisterb/strac/util"
+)
+
+// DefaultWalletDir is the directory wallets are created in if no wallet directory is given.
+var DefaultWalletDir = filepath.Join(util.AppData, "wallets")
+
+// hdKeystore is a Web3 Secret Storage (v3) keystore file with the derivation path of the key, which marks the file as
+// an hd wallet account.
+type hdKeystore struct {
+	Address string              `json:"address"`
+	Crypto  keystore.CryptoJSON `json:"crypto"`
+	Id      string              `json:"id"`
+	Version int                 `json:"version"`
+	Path    string              `json:"path"`
+}
+
+// CreateHD creates a hierarchical deterministic wallet in a directory with the wallet's name in walletDir. It derives
+// count accounts at m/44'/60'/0'/0/i from a new 24-word BIP-39 mnemonic, or from a mnemonic read from stdin if
+// mnemonicStdin is set, and stores each in a keystore file encrypted with the same passphrase. A new mnemonic is
+// printed once and is not stored.
+func CreateHD(name string, walletDir string, count int, mnemonicStdin bool) error {
+	if count < 1 {
+		return fmt.Errorf("at least 1 account must be derived")
+	}
+	if walletDir == "" {
+		walletDir = DefaultWalletDir
+	}
+	dir := filepath.Join(walletDir, name)
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return fmt.Errorf("wallet directory %s already exists and is not empty", dir)
+	}
+
+	var mnemonic string
+	if mnemonicStdin {
+		log.Info("Enter the mnemonic")
+		line, err := readLine(os.Stdin)
+		if err != nil {
+			return util.WrapError(err, "could not read mnemonic from stdin")
+		}
+		mnemonic = strings.Join(strings.Fields(line), " ")
+	} else {
+		entropy, err := bip39.NewEntropy(256)
+		if err != nil {
+			return util.WrapError(err, "could not generate entropy for mnemonic")
+		}
+		if mnemonic, err = bip39.NewMnemonic(entropy); err != nil {
+			return util.WrapError(err, "could not generate mnemonic")
+		}
+	}
+	// NewSeedWithErrorChecking rejects mnemonics with the wrong number of words, unknown words or a bad checksum.
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+	if err != nil {
+		return fmt.Errorf("invalid mnemonic: the mnemonic must be 12 to 24 words from the BIP-39 English word list with a valid checksum")
+	}
+
+	log.Infof("Creating hd wallet %s in %s...", name, dir)
+	log.Info("Enter the passphrase for the wallet's keystore files")
+	passphrase, err := util.GetPassPhrase(true)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return util.WrapError(err, "could not create directory %s", dir)
+	}
+	path := make(gethaccounts.DerivationPath, len(gethaccounts.DefaultBaseDerivationPath))
+	copy(path, gethaccounts.DefaultBaseDerivationPath)
+	for i := 0; i < count; i++ {
+		path[len(path)-1] = uint32(i)
+		privateKey, err := deriveKey(seed, path)
+		if err != nil {
+			return util.WrapError(err, "could not derive key at %v", path)
+		}
+		address := crypto.PubkeyToAddress(privateKey.PublicKey)
+		file, err := writeHDKeystore(dir, privateKey, path, *passphrase)
+		if err != nil {
+			return err
+		}
+		log.Infof("Account %d: %v at %v, keystore file %s", i, address.Hex(), path, file)
+	}
+	if !mnemonicStdin {
+		log.Infof("Wallet mnemonic: %s", mnemonic)
+		log.Warnf("Write down the mnemonic and store it securely offline. It is shown only this once, it is not stored, and it is the only way to recover the wallet's accounts if the keystore files or passphrase are lost. Anyone with the mnemonic controls the accounts.")
+	}
+	return nil
+}
+
+// readLine reads a line from r a byte at a time, so that input after the line, such as a piped passphrase, is left for
+// the passphrase prompt.
+func readLine(r io.Reader) (string, error) {
+	var line strings.Builder
+	b := make([]byte, 1)
+	for {
+		n, err := r.Read(b)
+		if n > 0 {
+			if b[0] == '\n' {
+				return line.String(), nil
+			}
+			line.WriteByte(b[0])
+		}
+		if err == io.EOF {
+			return line.String(), nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+}
+
+// deriveKey derives the private key at the BIP-32 path from a BIP-39 seed.
+func deriveKey(seed []byte, path gethaccounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	n := crypto.S256().Params().N
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	i := mac.Sum(nil)
+	key, chainCode := new(big.Int).SetBytes(i[:32]), i[32:]
+	if key.Sign() == 0 || key.Cmp(n) >= 0 {
+		return nil, fmt.Errorf("invalid master key")
+	}
+	for _, index := range path {
+		data := make([]byte, 0, 37)
+		if index >= 0x80000000 {
+			// Hardened child.
+			data = append(data, 0)
+			data = append(data, key.FillBytes(make([]byte, 32))...)
+		} else {
+			privateKey, err := crypto.ToECDSA(key.FillBytes(make([]byte, 32)))
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, crypto.CompressPubkey(&privateKey.PublicKey)...)
+		}
+		data = binary.BigEndian.AppendUint32(data, index)
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		i := mac.Sum(nil)
+		tweak := new(big.Int).SetBytes(i[:32])
+		if tweak.Cmp(n) >= 0 {
+			return nil, fmt.Errorf("invalid child key at index %d", index)
+		}
+		key = tweak.Add(tweak, key).Mod(tweak, n)
+		if key.Sign() == 0 {
+			return nil, fmt.Errorf("invalid child key at index %d", index)
+		}
+		chainCode = i[32:]
+	}
+	return crypto.ToECDSA(key.FillBytes(make([]byte, 32)))
+}
+
+// writeHDKeystore encrypts the private key into a keystore file named like geth's keystore files in dir, which can be
+// used with the commands that take a wallet directory.
+func writeHDKeystore(dir string, privateKey *ecdsa.PrivateKey, path gethaccounts.DerivationPath, passphrase string) (string, error) {
+	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+	cryptoJSON, err := keystore.EncryptDataV3(crypto.FromECDSA(privateKey), []byte(passphrase), keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		return "", util.WrapError(err, "could not encrypt key of account %v", address)
+	}
+	data, err := json.Marshal(&hdKeystore{
+		Address: hex.EncodeToString(address[:]),
+		Crypto:  cryptoJSON,
+		Id:      uuid.New().String(),
+		Version: 3,
+		Path:    path.String(),
+	})
+	if err != nil {
+		return "", err
+	}
+	file := filepath.Join(dir, fmt.Sprintf("UTC--%s--%s", time.Now().UTC().Format("2006-01-02T15-04-05.000000000Z"), hex.EncodeToString(address[:])))
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		return "", util.WrapError(err, "could not write keystore file %s", file)
+	}
+	return file, nil
+}
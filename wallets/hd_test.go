@@ -0,0 +1,58 @@
+package wallets
+
+import (
+	"encoding/hex"
+	"testing"
+
+	gethaccounts "github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// TestDeriveKeyBIP32 checks the private keys of test vector 1 of BIP-32.
+func TestDeriveKeyBIP32(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	tests := []struct {
+		path string
+		key  string
+	}{
+		{path: "m", key: "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"},
+		{path: "m/0'", key: "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{path: "m/0'/1", key: "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{path: "m/0'/1/2'", key: "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca"},
+		{path: "m/0'/1/2'/2", key: "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4"},
+		{path: "m/0'/1/2'/2/1000000000", key: "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+	}
+	for _, tt := range tests {
+		path := gethaccounts.DerivationPath{}
+		if tt.path != "m" {
+			var err error
+			if path, err = gethaccounts.ParseDerivationPath(tt.path); err != nil {
+				t.Fatalf("could not parse path %s: %v", tt.path, err)
+			}
+		}
+		key, err := deriveKey(seed, path)
+		if err != nil {
+			t.Errorf("deriveKey(%s) returned error: %v", tt.path, err)
+		} else if got := hex.EncodeToString(crypto.FromECDSA(key)); got != tt.key {
+			t.Errorf("deriveKey(%s) = %s, want %s", tt.path, got, tt.key)
+		}
+	}
+}
+
+// TestDeriveKeyMnemonic checks the first account derived from the well-known all-abandon test mnemonic.
+func TestDeriveKeyMnemonic(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+	if err != nil {
+		t.Fatalf("invalid mnemonic: %v", err)
+	}
+	path, _ := gethaccounts.ParseDerivationPath("m/44'/60'/0'/0/0")
+	key, err := deriveKey(seed, path)
+	if err != nil {
+		t.Fatalf("deriveKey returned error: %v", err)
+	}
+	if got, want := crypto.PubkeyToAddress(key.PublicKey).Hex(), "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"; got != want {
+		t.Errorf("account at %v = %s, want %s", path, got, want)
+	}
+}
@@ -0,0 +1,95 @@
+package wallets
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+	logging "github.com/ipfs/go-log/v2"
+)
+
+var log = logging.Logger("strac/wallets")
+
+type walletAccount struct {
+	File    string
+	Type    string
+	Address string
+}
+
+// List prints the accounts contained in the keystore files of the named wallet in walletDir. The wallet type can be
+// nd for non-deterministic wallets of individual keystore files or hd for hierarchical deterministic wallets whose
+// keystores record a derivation path.
+func List(walletType string, name string, walletDir string) error {
+	if walletType != "nd" && walletType != "hd" {
+		return fmt.Errorf("unknown wallet type %s: use nd or hd", walletType)
+	}
+	dir := filepath.Join(walletDir, name)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("could not read wallet directory %s: %v", dir, err)
+	}
+	accounts := make([]*walletAccount, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		account, err := readAccount(path)
+		if err != nil {
+			log.Warnf("Skipping %s: %v", path, err)
+			continue
+		}
+		if account.Type == walletType {
+			accounts = append(accounts, account)
+		}
+	}
+	if len(accounts) == 0 {
+		log.Infof("No %s wallet accounts found in %s.", walletType, dir)
+		return nil
+	}
+
+	log.Infof("Wallet %s (%s) in %s:", name, walletType, dir)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ADDRESS\tTYPE\tFILE")
+	for _, a := range accounts {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", a.Address, a.Type, a.File)
+	}
+	return w.Flush()
+}
+
+// readAccount reads the address and wallet type of a Web3 Secret Storage (v3) or EIP-2335 keystore file.
+func readAccount(path string) (*walletAccount, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var keystore struct {
+		Address string          `json:"address"`
+		PubKey  string          `json:"pubkey"`
+		Path    string          `json:"path"`
+		Crypto  json.RawMessage `json:"crypto"`
+	}
+	if err := json.Unmarshal(data, &keystore); err != nil {
+		return nil, fmt.Errorf("not a JSON keystore file")
+	}
+	if len(keystore.Crypto) == 0 {
+		return nil, fmt.Errorf("no crypto section found")
+	}
+	account := &walletAccount{File: filepath.Base(path), Type: "nd"}
+	if keystore.Path != "" {
+		account.Type = "hd"
+	}
+	switch {
+	case keystore.Address != "" && common.IsHexAddress(keystore.Address):
+		account.Address = common.HexToAddress(keystore.Address).Hex()
+	case keystore.PubKey != "":
+		account.Address = "0x" + strings.TrimPrefix(keystore.PubKey, "0x")
+	default:
+		return nil, fmt.Errorf("no address found")
+	}
+	return account, nil
+}